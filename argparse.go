@@ -3,9 +3,18 @@ package argparse
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // DisableDescription can be assigned as a command or arguments description to hide it from the Usage output
@@ -32,8 +41,52 @@ type Command struct {
 	parent      *Command
 	HelpFunc    func(c *Command, msg interface{}) string
 	exitOnHelp  bool
+
+	negativeNumbers NegativeNumbersMode
+	onDuplicate     DuplicatePolicy
+	allowAbbrev     bool
+	normalize       func(name string) string
+	errorWriter     io.Writer
+	multiCharShort  bool
+	singleDashLong  bool
+	notInterspersed bool
+	extendedDur     bool
+	timeLocation    *time.Location
+
+	remainder       []string
+	remainderValues []string
+
+	origins []string // Where arguments being parsed come from, set on the top level Command only
 }
 
+// DuplicatePolicy specifies what happens when String, Int, Float, Selector or File argument is present more than once
+type DuplicatePolicy int
+
+const (
+	// DuplicateDefault is used in Options to follow the policy of the Command, which is DuplicateError unless changed
+	DuplicateDefault DuplicatePolicy = iota
+	// DuplicateError returns an error when argument is present more than once
+	DuplicateError
+	// DuplicateLastWins keeps the last value of the argument
+	DuplicateLastWins
+	// DuplicateFirstWins keeps the first value of the argument. Following values are still converted and validated,
+	// so bad values are reported, but then ignored
+	DuplicateFirstWins
+)
+
+// NegativeNumbersMode specifies how arguments that look like negative numbers (such as `-5` or `-0.5`) are treated
+type NegativeNumbersMode int
+
+const (
+	// NegativeNumbersAuto treats negative numbers as values, unless Command or any of its parents has
+	// an argument with a digit as short name. This is the same as Python's argparse does.
+	NegativeNumbersAuto NegativeNumbersMode = iota
+	// NegativeNumbersAsValues always treats negative numbers as values
+	NegativeNumbersAsValues
+	// NegativeNumbersAsOptions always treats negative numbers as shorthand arguments
+	NegativeNumbersAsOptions
+)
+
 // GetName exposes Command's name field
 func (o Command) GetName() string {
 	return o.name
@@ -80,6 +133,8 @@ func (o *Command) Help(msg interface{}) string {
 // functions.
 type Parser struct {
 	Command
+
+	fromFilePrefix rune
 }
 
 // Options are specific options for every argument. They can be provided if necessary.
@@ -98,8 +153,13 @@ type Parser struct {
 //
 // Options.Validate - is a validation function. Using this field anyone can implement a custom validation for argument.
 // If provided and argument is present, then function is called. If argument also consumes any following values
-// (e.g. as String does), then these are provided as args to function. If validation fails the error must be returned,
-// which will be the output of `Parser.Parse` method.
+// (e.g. as String does), then these are provided as args to function. Values of argument with Separator are split
+// before validation. If validation fails the error must be returned, which will be the output of `Parser.Parse` method.
+//
+// Options.Check - is a validation function for the converted value. It is called once after parsing is finished
+// if argument was present or got a default value, so unlike Validate it also checks defaults. Value has the type
+// returned pointer points to, e.g. int for Int or []string for StringList, and arguments imported with AddFlagSet
+// get their flag.Value. If check fails the error must be returned, which will be the output of `Parser.Parse` method.
 //
 // Options.Help - A help message to be displayed in Usage output. Can be of any length as the message will be
 // formatted to fit max screen width of 100 characters.
@@ -107,11 +167,61 @@ type Parser struct {
 // Options.Default - A default value for an argument. This value will be assigned to the argument at the end of parsing
 // in case if this argument was not supplied on command line. File default value is a string which it will be open with
 // provided options. In case if provided value type does not match expected, the error will be returned on run-time.
+//
+// Options.NArgs - allows list and map arguments (StringList, IntList, Int64List, UintList, Uint64List, FloatList,
+// DurationList, FileList, IPList, IPNetList, HostPortList, URLList, RegexpList, StringMap, IntMap and FloatMap)
+// to consume several following values on each occurrence, such as `--files a b c`. Values are consumed until the next
+// argument, `--` or the end of arguments. Possible values are: "N" (exactly N values), "N-M" (from N to M values),
+// "N+" (at least N values), "+" (at least one value) and "*" (any number of values, including none).
+//
+// Options.Separator - allows the same list and map arguments as NArgs does to split each value on provided separator,
+// such as `--tags a,b,c`. Separator can be part of a value if it is escaped with backslash (`a\,b`) or if the whole
+// value is quoted (`"a,b"`). Any other backslashes and quotes are kept as is.
+// Default value can be given as a single string with separated values as well. Validate gets already split values.
+//
+// Options.ConstValue - makes value of String, Int, Float, Duration or Selector argument optional, same as GNU
+// `--color[=WHEN]`. If argument is given without value, then ConstValue is assigned to it. Value can only be given
+// using equals char (`--color=never`) or attached to shorthand argument (`-cnever`), so `--color never` leaves `never`
+// as positional.
+// ConstValue type must match the argument type.
+//
+// Options.Metavar - name of the value shown in usage of argument with NArgs or ConstValue, such as `FILE` in
+// `--files FILE [FILE ...]` or `WHEN` in `--color[=WHEN]`. Upper-cased long name is used if not provided.
+//
+// Options.Aliases - additional short and long names of the argument, e.g. `{Name: "--config-dir"}`.
+// Alias can be marked as Deprecated, then it still works, but its usage prints a warning naming the replacement
+// to the error writer (see Parser.SetErrorWriter).
+//
+// Options.Duplicate - specifies what happens when single-value argument (String, Int, Int64, Uint, Uint64, ByteSize,
+// Float, Duration, Time, Selector, File, IP, IPNet, HostPort, URL or Regexp) is present more than once,
+// see DuplicatePolicy for possible values. If not provided, the policy of the Command is used (see OnDuplicate).
+// When File argument is replaced with another value, the replaced file is closed.
+// For StringMap, IntMap and FloatMap the policy applies to keys given more than once instead.
+//
+// Options.KeySeparator - separator of key and value of StringMap, IntMap and FloatMap arguments, `=` by default.
+// Key ends at the first separator, so value can contain it (`--set a=b=c` sets `a` to `b=c`).
+//
+// Options.ValidateKey - is a validation function for keys of StringMap, IntMap and FloatMap arguments.
+// If validation fails the error must be returned, which will be the output of `Parser.Parse` method.
+//
+// Options.POSIX - makes Regexp and RegexpList compile expressions with regexp.CompilePOSIX, which restricts
+// syntax to POSIX ERE (egrep) and uses leftmost-longest matching.
 type Options struct {
-	Required bool
-	Validate func(args []string) error
-	Help     string
-	Default  interface{}
+	Required   bool
+	Validate   func(args []string) error
+	Check      func(value interface{}) error
+	Help       string
+	Default    interface{}
+	NArgs      string
+	Separator  string
+	ConstValue interface{}
+	Metavar    string
+	Duplicate  DuplicatePolicy
+	Aliases    []Alias
+
+	KeySeparator string
+	ValidateKey  func(key string) error
+	POSIX        bool
 
 	// Private modifiers
 	positional bool
@@ -149,6 +259,15 @@ func (o *Command) NewCommand(name string, description string) *Command {
 	c.description = description
 	c.parsed = false
 	c.parent = o
+	c.negativeNumbers = o.negativeNumbers
+	c.onDuplicate = o.onDuplicate
+	c.allowAbbrev = o.allowAbbrev
+	c.normalize = o.normalize
+	c.errorWriter = o.errorWriter
+	c.multiCharShort = o.multiCharShort
+	c.singleDashLong = o.singleDashLong
+	c.extendedDur = o.extendedDur
+	c.timeLocation = o.timeLocation
 	if !disableHelp {
 		c.help("h", "help")
 		c.exitOnHelp = true
@@ -188,73 +307,922 @@ func (o *Command) ExitOnHelp(b bool) {
 	for _, c := range o.commands {
 		c.ExitOnHelp(b)
 	}
-}
+}
+
+// NegativeNumbers sets how arguments that look like negative numbers are treated by Command
+// and all its sub-commands. See NegativeNumbersMode for possible values.
+func (o *Command) NegativeNumbers(mode NegativeNumbersMode) {
+	o.negativeNumbers = mode
+	for _, c := range o.commands {
+		c.NegativeNumbers(mode)
+	}
+}
+
+// OnDuplicate sets what happens when String, Int, Float, Selector or File argument of Command
+// and all its sub-commands is present more than once. Options.Duplicate takes precedence over it.
+// See DuplicatePolicy for possible values.
+func (o *Command) OnDuplicate(policy DuplicatePolicy) {
+	o.onDuplicate = policy
+	for _, c := range o.commands {
+		c.OnDuplicate(policy)
+	}
+}
+
+// Interspersed sets whether arguments of Command can be mixed with its positionals, which is the default.
+// With Interspersed(false) parsing of arguments stops at the first positional (or at "--") same as POSIX `getopt`
+// does when POSIXLY_CORRECT is set, so `wrapper -v cmd -v` sets `-v` of wrapper only once.
+// The first positional and everything after it are left to positionals, which makes it useful together
+// with StringListPositional for wrapper tools such as `timeout` or `nice`.
+// Unlike most settings, it applies to this Command only and is not inherited by sub-commands.
+func (o *Command) Interspersed(b bool) {
+	o.notInterspersed = !b
+}
+
+// ExtendedDurations allows Duration arguments of Command and all its sub-commands to use days (`3d`)
+// and weeks (`2w`) in addition to units accepted by time.ParseDuration, such as `1w2d12h`.
+// A day is always 24 hours and a week is always 7 days.
+func (o *Command) ExtendedDurations(b bool) {
+	o.extendedDur = b
+	for _, c := range o.commands {
+		c.ExtendedDurations(b)
+	}
+}
+
+// TimeLocation sets time zone used by Time arguments of Command and all its sub-commands to interpret
+// times without time zone, Unix time and relative times such as `today`. Default is time.Local.
+func (o *Command) TimeLocation(loc *time.Location) {
+	o.timeLocation = loc
+	for _, c := range o.commands {
+		c.TimeLocation(loc)
+	}
+}
+
+// AllowAbbrev allows long arguments to be abbreviated to any unique prefix, such as `--verb` for `--verbose`.
+// Prefix must be unique among arguments of the active command and all its parents, otherwise parsing fails
+// with an error listing possible candidates. Exact match of argument name always takes precedence.
+func (o *Parser) AllowAbbrev(b bool) {
+	o.setAllowAbbrev(b)
+}
+
+// setAllowAbbrev sets allowAbbrev field of Command and all its sub-commands
+func (o *Command) setAllowAbbrev(b bool) {
+	o.allowAbbrev = b
+	for _, c := range o.commands {
+		c.setAllowAbbrev(b)
+	}
+}
+
+// NormalizeCase is a name normalizer that makes matching case-insensitive, see Parser.Normalize
+func NormalizeCase(name string) string {
+	return strings.ToLower(name)
+}
+
+// NormalizeUnderscores is a name normalizer that makes "_" and "-" equivalent, see Parser.Normalize
+func NormalizeUnderscores(name string) string {
+	return strings.Replace(name, "_", "-", -1)
+}
+
+// Normalize sets functions that are applied (in provided order) to names before they are compared.
+// They are used when matching long argument names, command names and Selector values, so for example
+// `parser.Normalize(argparse.NormalizeCase, argparse.NormalizeUnderscores)` allows `--Dry_Run` to match `--dry-run`.
+// Usage still shows names as they were defined. Calling Normalize without arguments disables normalization.
+// It should be called before arguments are added, so that names matching after normalization are rejected.
+func (o *Parser) Normalize(normalizers ...func(name string) string) {
+	var normalize func(name string) string
+	if len(normalizers) > 0 {
+		normalize = func(name string) string {
+			for _, f := range normalizers {
+				name = f(name)
+			}
+			return name
+		}
+	}
+	o.setNormalize(normalize)
+}
+
+// setNormalize sets normalize field of Command and all its sub-commands
+func (o *Command) setNormalize(normalize func(name string) string) {
+	o.normalize = normalize
+	for _, c := range o.commands {
+		c.setNormalize(normalize)
+	}
+}
+
+// normalizeName applies Command's normalization to name, if any
+func (o *Command) normalizeName(name string) string {
+	if o == nil || o.normalize == nil {
+		return name
+	}
+	return o.normalize(name)
+}
+
+// MultiCharShortNames allows shorthand names to be longer than one character, such as `-vv` or `-cp`
+// (same as in Java or Go `flag` style tools). Combining shorthand arguments into one (`-xvf`) is disabled
+// in this mode and value can only be attached to shorthand argument using equals char (`-cp=value`).
+// It must be called before arguments with such names are added.
+func (o *Parser) MultiCharShortNames(b bool) {
+	o.setMultiCharShort(b)
+}
+
+// setMultiCharShort sets multiCharShort field of Command and all its sub-commands
+func (o *Command) setMultiCharShort(b bool) {
+	o.multiCharShort = b
+	for _, c := range o.commands {
+		c.setMultiCharShort(b)
+	}
+}
+
+// SingleDashLongNames allows long names to be given with single dash (`-config=x -verbose`), same as Go `flag`
+// package does. This allows tools migrated from `flag` package to keep backward compatibility.
+// Long names take precedence over combined shorthand arguments, so `-verbose` is never treated as `-v -e -r ...`
+// if `verbose` is a long name. Flag values can be set explicitly with equals char (`-verbose=false`).
+func (o *Parser) SingleDashLongNames(b bool) {
+	o.setSingleDashLong(b)
+}
+
+// setSingleDashLong sets singleDashLong field of Command and all its sub-commands
+func (o *Command) setSingleDashLong(b bool) {
+	o.singleDashLong = b
+	for _, c := range o.commands {
+		c.setSingleDashLong(b)
+	}
+}
+
+// SetErrorWriter sets writer that Parser uses to print warnings, such as usage of deprecated argument aliases.
+// Default is os.Stderr.
+func (o *Parser) SetErrorWriter(w io.Writer) {
+	o.setErrorWriter(w)
+}
+
+// setErrorWriter sets errorWriter field of Command and all its sub-commands
+func (o *Command) setErrorWriter(w io.Writer) {
+	o.errorWriter = w
+	for _, c := range o.commands {
+		c.setErrorWriter(w)
+	}
+}
+
+// getErrorWriter returns writer for warnings of Command
+func (o *Command) getErrorWriter() io.Writer {
+	if o == nil || o.errorWriter == nil {
+		return os.Stderr
+	}
+	return o.errorWriter
+}
+
+// SetHelp removes the previous help argument, and creates a new one with the desired sname/lname
+func (o *Parser) SetHelp(sname, lname string) {
+	o.DisableHelp()
+	o.help(sname, lname)
+}
+
+// Flag Creates new flag type of argument, which is boolean value showing if argument was provided or not.
+// Takes short name, long name and pointer to options (optional).
+// Short name must be single character, but can be omitted by giving empty string.
+// Long name is required.
+// Returns pointer to boolean with starting value `false`. If Parser finds the flag
+// provided on Command line arguments, then the value is changed to true.
+// Value can also be set explicitly using equals char, such as `--flag=false`, if Parser.SingleDashLongNames is enabled.
+// Set of Flag and FlagCounter shorthand arguments can be combined together such as `tar -cvaf foo.tar foo`
+func (o *Command) Flag(short string, long string, opts *Options) *bool {
+	var result bool
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    1,
+		opts:    opts,
+		unique:  true,
+		argType: Flag,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Flag: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// FlagCounter Creates new flagCounter type of argument, which is integer value showing the number of times the argument has been provided.
+// Takes short name, long name and pointer to options (optional).
+// Short name must be single character, but can be omitted by giving empty string.
+// Long name is required.
+// Returns pointer to integer with starting value `0`. Each time Parser finds the flag
+// provided on Command line arguments, the value is incremented by 1.
+// Set of FlagCounter and Flag shorthand arguments can be combined together such as `tar -cvaf foo.tar foo`
+func (o *Command) FlagCounter(short string, long string, opts *Options) *int {
+	var result int
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    1,
+		opts:    opts,
+		unique:  false,
+		argType: FlagCounter,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add FlagCounter: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// String creates new string argument, which will return whatever follows the argument on CLI.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options
+func (o *Command) String(short string, long string, opts *Options) *string {
+	var result string
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: String,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add String: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func String documentation
+func (o *Command) StringPositional(opts *Options) *string {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.String("", name, opts)
+}
+
+// Int creates new int argument, which will attempt to parse following argument as int.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) Int(short string, long string, opts *Options) *int {
+	var result int
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Int,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Int: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func Int documentation
+func (o *Command) IntPositional(opts *Options) *int {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.Int("", name, opts)
+}
+
+// Int64 creates new 64-bit integer argument, which will attempt to parse following argument as int64.
+// Same as Go integer literals, value can be given in decimal, hexadecimal (`0x1F`), octal (`0o755` or `0755`)
+// or binary (`0b101`) form and can use underscores as digit separators (`1_000_000`).
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails or value does not fit into int64 parser.Parse() will return an error.
+func (o *Command) Int64(short string, long string, opts *Options) *int64 {
+	var result int64
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Int64,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Int64: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func Int64 documentation
+func (o *Command) Int64Positional(opts *Options) *int64 {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.Int64("", name, opts)
+}
+
+// Uint creates new unsigned integer argument, which will attempt to parse following argument as uint.
+// Same as Go integer literals, value can be given in decimal, hexadecimal (`0x1F`), octal (`0o755` or `0755`)
+// or binary (`0b101`) form and can use underscores as digit separators (`1_000_000`).
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails or value does not fit into uint parser.Parse() will return an error.
+func (o *Command) Uint(short string, long string, opts *Options) *uint {
+	var result uint
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Uint,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Uint: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func Uint documentation
+func (o *Command) UintPositional(opts *Options) *uint {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.Uint("", name, opts)
+}
+
+// Uint64 creates new 64-bit unsigned integer argument, which will attempt to parse following argument as uint64.
+// Same as Go integer literals, value can be given in decimal, hexadecimal (`0x1F`), octal (`0o755` or `0755`)
+// or binary (`0b101`) form and can use underscores as digit separators (`1_000_000`).
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails or value does not fit into uint64 parser.Parse() will return an error.
+func (o *Command) Uint64(short string, long string, opts *Options) *uint64 {
+	var result uint64
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Uint64,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Uint64: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func Uint64 documentation
+func (o *Command) Uint64Positional(opts *Options) *uint64 {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.Uint64("", name, opts)
+}
+
+// ByteSize creates new byte size argument, which will attempt to parse following argument as number of bytes.
+// Value can have SI (`k`, `kB`, `M`, `MB`, `G`, `GB`, `T`, `TB`, ...) or IEC (`KiB`, `MiB`, `GiB`, `TiB`, ...) suffix
+// in any case and can be decimal, such as `10MB` or `1.5GiB`. Fractions of a byte are truncated.
+// Default value must be uint64 and is shown in help in human-readable form, such as `64MiB`.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails or value does not fit into uint64 parser.Parse() will return an error.
+func (o *Command) ByteSize(short string, long string, opts *Options) *uint64 {
+	var result uint64
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: ByteSize,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add ByteSize: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// Float creates new float argument, which will attempt to parse following argument as float64.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) Float(short string, long string, opts *Options) *float64 {
+	var result float64
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Float,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Float: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func Float documentation
+func (o *Command) FloatPositional(opts *Options) *float64 {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.Float("", name, opts)
+}
+
+// Duration creates new duration argument, which will attempt to parse following argument as time.Duration
+// using time.ParseDuration, such as `--timeout 1m30s`. See Command.ExtendedDurations to allow days and weeks.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) Duration(short string, long string, opts *Options) *time.Duration {
+	var result time.Duration
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Duration,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Duration: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func Duration documentation
+func (o *Command) DurationPositional(opts *Options) *time.Duration {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.Duration("", name, opts)
+}
+
+// Time creates new time argument, which will attempt to parse following argument as time.Time.
+// RFC3339 (`2006-01-02T15:04:05Z07:00`) is always accepted, additional layouts (see time.Parse) can be provided
+// in layouts, such as `[]string{"2006-01-02", "2006-01-02 15:04"}`. Following values are accepted as well:
+// Unix time in seconds (`1700000000`), `now`, `today`, `yesterday` and `tomorrow` (the last three mean midnight)
+// and duration relative to now starting with sign (`-2h`, `+30m`), see Command.ExtendedDurations.
+// Times without time zone are in time zone set by Command.TimeLocation.
+// Takes as arguments short name (must be single character or an empty string)
+// long name, layouts and (optional) options.
+// If parsing fails parser.Parse() will return an error listing accepted formats.
+func (o *Command) Time(short string, long string, layouts []string, opts *Options) *time.Time {
+	var result time.Time
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		layouts: append([]string{time.RFC3339}, layouts...),
+		argType: Time,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Time: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// IP creates new IP address argument, which will attempt to parse following argument as IPv4 or IPv6 address,
+// such as `--listen 10.0.0.1` or `--listen ::1`. Default value can be net.IP or a string, which is parsed same way.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) IP(short string, long string, opts *Options) *net.IP {
+	var result net.IP
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: IP,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add IP: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func IP documentation
+func (o *Command) IPPositional(opts *Options) *net.IP {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.IP("", name, opts)
+}
+
+// IPNet creates new network argument, which will attempt to parse following argument as IP network in CIDR notation,
+// such as `--cidr 10.0.0.0/8`. Resulting IP is the network address, so `10.1.2.3/8` results in `10.0.0.0/8`.
+// Default value can be net.IPNet or a string, which is parsed same way.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) IPNet(short string, long string, opts *Options) *net.IPNet {
+	var result net.IPNet
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: IPNet,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add IPNet: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func IPNet documentation
+func (o *Command) IPNetPositional(opts *Options) *net.IPNet {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.IPNet("", name, opts)
+}
+
+// HostPort creates new host and port argument, such as `--peer example.com:8080`, `--peer [::1]:8080` or `--listen :8080`.
+// Host must be empty, IP address or valid host name and port must be a number from 0 to 65535.
+// Value is returned as it was given, ready to be used with net.Dial or net.Listen.
+// Default value must be a string, which is parsed same way.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) HostPort(short string, long string, opts *Options) *string {
+	var result string
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: HostPort,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add HostPort: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func HostPort documentation
+func (o *Command) HostPortPositional(opts *Options) *string {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.HostPort("", name, opts)
+}
+
+// URL creates new URL argument, which will attempt to parse following argument as absolute URL (with scheme),
+// such as `--endpoint https://example.com/api`. If schemes are provided, then only URLs with one of these schemes
+// (case-insensitive) are allowed, e.g. `[]string{"http", "https"}`. Default value can be url.URL or a string, which is parsed same way.
+// Takes as arguments short name (must be single character or an empty string)
+// long name, allowed schemes and (optional) options.
+// If parsing fails parser.Parse() will return an error.
+func (o *Command) URL(short string, long string, schemes []string, opts *Options) *url.URL {
+	var result url.URL
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		schemes: schemes,
+		argType: URL,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add URL: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func URL documentation
+func (o *Command) URLPositional(schemes []string, opts *Options) *url.URL {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.URL("", name, schemes, opts)
+}
+
+// Regexp creates new regular expression argument, which is compiled while parsing, such as `--include '\.go$'`.
+// Result is nil if argument was not provided. See Options.POSIX to use POSIX syntax.
+// Default value can be *regexp.Regexp or a string, which is compiled same way.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// If expression fails to compile parser.Parse() will return the compile error prefixed with argument name.
+func (o *Command) Regexp(short string, long string, opts *Options) **regexp.Regexp {
+	var result *regexp.Regexp
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  true,
+		argType: Regexp,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add Regexp: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// IntRanges creates new integer ranges argument, which parses comma separated ranges of integers, such as
+// `--shards 0-3,7`. Each range can be a single value (`7`), inclusive range (`0-3`), open-ended range ending
+// at max (`5-`) or range with a step (`0-100:10`). All values must be from min to max (inclusive).
+// Result is a list of ranges sorted by start, which is never expanded into separate integers, so even `0-` with
+// huge max is cheap. Use IntSet.Contains to check for a value and IntSet.Values to get integers, up to a limit.
+// Argument can be present multiple times, then all its ranges are merged.
+// Default value can be IntSet or a string, which is parsed same way.
+// Takes as arguments short name (must be single character or an empty string)
+// long name, min and max allowed values and (optional) options.
+// If parsing fails parser.Parse() will return an error naming position of the bad range.
+func (o *Command) IntRanges(short string, long string, min int, max int, opts *Options) *IntSet {
+	result := make(IntSet, 0)
+
+	a := &arg{
+		result:   &result,
+		sname:    short,
+		lname:    long,
+		size:     2,
+		opts:     opts,
+		unique:   false,
+		rangeMin: min,
+		rangeMax: max,
+		argType:  IntRanges,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add IntRanges: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// File creates new file argument, which is when provided will check if file exists or attempt to create it
+// depending on provided flags (same as for os.OpenFile).
+// It takes same as all other arguments short and long names, additionally it takes flags that specify
+// in which mode the file should be open (see os.OpenFile for details on that), file permissions that
+// will be applied to a file and argument options.
+// Returns a pointer to os.File which will be set to opened file on success. On error the Parser.Parse
+// will return error and the pointer might be nil.
+func (o *Command) File(short string, long string, flag int, perm os.FileMode, opts *Options) *os.File {
+	var result os.File
+
+	a := &arg{
+		result:   &result,
+		sname:    short,
+		lname:    long,
+		size:     2,
+		opts:     opts,
+		unique:   true,
+		fileFlag: flag,
+		filePerm: perm,
+		argType:  File,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add File: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// See func File documentation
+func (o *Command) FilePositional(flag int, perm os.FileMode, opts *Options) *os.File {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.File("", name, flag, perm, opts)
+}
+
+// List creates new list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of default type values which is strings.
+// If no argument provided, then the list is empty.
+// Takes same parameters as String.
+// Returns a pointer the list of strings.
+func (o *Command) List(short string, long string, opts *Options) *[]string {
+	return o.StringList(short, long, opts)
+}
+
+// StringList creates new string list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of strings. If no argument
+// provided, then the list is empty. Takes same parameters as String
+// Returns a pointer the list of strings.
+func (o *Command) StringList(short string, long string, opts *Options) *[]string {
+	result := make([]string, 0)
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: StringList,
+	}
+
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add StringList: %s", err.Error()))
+	}
+
+	return &result
+}
+
+// StringListPositional creates new string list positional argument, which collects all remaining positional values.
+// It must be the last positional of Command, since no values are left for positionals after it.
+// Together with Interspersed(false) it collects the rest of command line, such as command to run by wrapper tool.
+func (o *Command) StringListPositional(opts *Options) *[]string {
+	if opts == nil {
+		opts = &Options{}
+	}
+	opts.positional = true
+
+	// We supply a long name for documentation and internal logic
+	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
+	return o.StringList("", name, opts)
+}
+
+// IntList creates new integer list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of integers. If no argument
+// provided, then the list is empty. Takes same parameters as Int
+// Returns a pointer the list of integers.
+func (o *Command) IntList(short string, long string, opts *Options) *[]int {
+	result := make([]int, 0)
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: IntList,
+	}
 
-// SetHelp removes the previous help argument, and creates a new one with the desired sname/lname
-func (o *Parser) SetHelp(sname, lname string) {
-	o.DisableHelp()
-	o.help(sname, lname)
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add IntList: %s", err.Error()))
+	}
+
+	return &result
 }
 
-// Flag Creates new flag type of argument, which is boolean value showing if argument was provided or not.
-// Takes short name, long name and pointer to options (optional).
-// Short name must be single character, but can be omitted by giving empty string.
-// Long name is required.
-// Returns pointer to boolean with starting value `false`. If Parser finds the flag
-// provided on Command line arguments, then the value is changed to true.
-// Set of Flag and FlagCounter shorthand arguments can be combined together such as `tar -cvaf foo.tar foo`
-func (o *Command) Flag(short string, long string, opts *Options) *bool {
-	var result bool
+// Int64List creates new 64-bit integer list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of int64 values. If no argument
+// provided, then the list is empty. Takes same parameters as Int64
+// Returns a pointer the list of int64 values.
+func (o *Command) Int64List(short string, long string, opts *Options) *[]int64 {
+	result := make([]int64, 0)
 
 	a := &arg{
 		result:  &result,
 		sname:   short,
 		lname:   long,
-		size:    1,
+		size:    2,
 		opts:    opts,
-		unique:  true,
-		argType: Flag,
+		unique:  false,
+		argType: Int64List,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add Flag: %s", err.Error()))
+		panic(fmt.Errorf("unable to add Int64List: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// FlagCounter Creates new flagCounter type of argument, which is integer value showing the number of times the argument has been provided.
-// Takes short name, long name and pointer to options (optional).
-// Short name must be single character, but can be omitted by giving empty string.
-// Long name is required.
-// Returns pointer to integer with starting value `0`. Each time Parser finds the flag
-// provided on Command line arguments, the value is incremented by 1.
-// Set of FlagCounter and Flag shorthand arguments can be combined together such as `tar -cvaf foo.tar foo`
-func (o *Command) FlagCounter(short string, long string, opts *Options) *int {
-	var result int
+// UintList creates new unsigned integer list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of uint values. If no argument
+// provided, then the list is empty. Takes same parameters as Uint
+// Returns a pointer the list of uint values.
+func (o *Command) UintList(short string, long string, opts *Options) *[]uint {
+	result := make([]uint, 0)
 
 	a := &arg{
 		result:  &result,
 		sname:   short,
 		lname:   long,
-		size:    1,
+		size:    2,
 		opts:    opts,
 		unique:  false,
-		argType: FlagCounter,
+		argType: UintList,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add FlagCounter: %s", err.Error()))
+		panic(fmt.Errorf("unable to add UintList: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// String creates new string argument, which will return whatever follows the argument on CLI.
-// Takes as arguments short name (must be single character or an empty string)
-// long name and (optional) options
-func (o *Command) String(short string, long string, opts *Options) *string {
-	var result string
+// Uint64List creates new 64-bit unsigned integer list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of uint64 values. If no argument
+// provided, then the list is empty. Takes same parameters as Uint64
+// Returns a pointer the list of uint64 values.
+func (o *Command) Uint64List(short string, long string, opts *Options) *[]uint64 {
+	result := make([]uint64, 0)
 
 	a := &arg{
 		result:  &result,
@@ -262,35 +1230,47 @@ func (o *Command) String(short string, long string, opts *Options) *string {
 		lname:   long,
 		size:    2,
 		opts:    opts,
-		unique:  true,
-		argType: String,
+		unique:  false,
+		argType: Uint64List,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add String: %s", err.Error()))
+		panic(fmt.Errorf("unable to add Uint64List: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// See func String documentation
-func (o *Command) StringPositional(opts *Options) *string {
-	if opts == nil {
-		opts = &Options{}
+// FloatList creates new float list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of float64 values. If no argument
+// provided, then the list is empty. Takes same parameters as Float
+// Returns a pointer the list of float64 values.
+func (o *Command) FloatList(short string, long string, opts *Options) *[]float64 {
+	result := make([]float64, 0)
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: FloatList,
 	}
-	opts.positional = true
 
-	// We supply a long name for documentation and internal logic
-	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
-	return o.String("", name, opts)
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add FloatList: %s", err.Error()))
+	}
+
+	return &result
 }
 
-// Int creates new int argument, which will attempt to parse following argument as int.
-// Takes as arguments short name (must be single character or an empty string)
-// long name and (optional) options.
-// If parsing fails parser.Parse() will return an error.
-func (o *Command) Int(short string, long string, opts *Options) *int {
-	var result int
+// DurationList creates new duration list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of time.Duration values. If no argument
+// provided, then the list is empty. Takes same parameters as Duration
+// Returns a pointer the list of time.Duration values.
+func (o *Command) DurationList(short string, long string, opts *Options) *[]time.Duration {
+	result := make([]time.Duration, 0)
 
 	a := &arg{
 		result:  &result,
@@ -298,35 +1278,51 @@ func (o *Command) Int(short string, long string, opts *Options) *int {
 		lname:   long,
 		size:    2,
 		opts:    opts,
-		unique:  true,
-		argType: Int,
+		unique:  false,
+		argType: DurationList,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add Int: %s", err.Error()))
+		panic(fmt.Errorf("unable to add DurationList: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// See func Int documentation
-func (o *Command) IntPositional(opts *Options) *int {
-	if opts == nil {
-		opts = &Options{}
+// StringMap creates new map argument, which collects `key=value` pairs into map of strings.
+// This is the argument that is allowed to be present multiple times on CLI, such as
+// `--label team=infra --label tier=web` or `--label=team=infra`. Separator of key and value can be changed
+// with Options.KeySeparator and several pairs can be given at once with Options.Separator (`--label a=1,b=2`).
+// Key given more than once is an error, unless Options.Duplicate allows it. Keys can be validated with
+// Options.ValidateKey. Default value must be map[string]string.
+// Takes as arguments short name (must be single character or an empty string)
+// long name and (optional) options.
+// Returns a pointer the map, which is empty if no argument provided.
+func (o *Command) StringMap(short string, long string, opts *Options) *map[string]string {
+	result := make(map[string]string)
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: StringMap,
 	}
-	opts.positional = true
 
-	// We supply a long name for documentation and internal logic
-	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
-	return o.Int("", name, opts)
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add StringMap: %s", err.Error()))
+	}
+
+	return &result
 }
 
-// Float creates new float argument, which will attempt to parse following argument as float64.
-// Takes as arguments short name (must be single character or an empty string)
-// long name and (optional) options.
-// If parsing fails parser.Parse() will return an error.
-func (o *Command) Float(short string, long string, opts *Options) *float64 {
-	var result float64
+// IntMap creates new map argument same as StringMap, but values are parsed same as Int does.
+// Default value must be map[string]int.
+// Returns a pointer the map of int values, which is empty if no argument provided.
+func (o *Command) IntMap(short string, long string, opts *Options) *map[string]int {
+	result := make(map[string]int)
 
 	a := &arg{
 		result:  &result,
@@ -334,84 +1330,93 @@ func (o *Command) Float(short string, long string, opts *Options) *float64 {
 		lname:   long,
 		size:    2,
 		opts:    opts,
-		unique:  true,
-		argType: Float,
+		unique:  false,
+		argType: IntMap,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add Float: %s", err.Error()))
+		panic(fmt.Errorf("unable to add IntMap: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// See func Float documentation
-func (o *Command) FloatPositional(opts *Options) *float64 {
-	if opts == nil {
-		opts = &Options{}
+// FloatMap creates new map argument same as StringMap, but values are parsed same as Float does.
+// Default value must be map[string]float64.
+// Returns a pointer the map of float64 values, which is empty if no argument provided.
+func (o *Command) FloatMap(short string, long string, opts *Options) *map[string]float64 {
+	result := make(map[string]float64)
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: FloatMap,
 	}
-	opts.positional = true
 
-	// We supply a long name for documentation and internal logic
-	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
-	return o.Float("", name, opts)
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add FloatMap: %s", err.Error()))
+	}
+
+	return &result
 }
 
-// File creates new file argument, which is when provided will check if file exists or attempt to create it
-// depending on provided flags (same as for os.OpenFile).
-// It takes same as all other arguments short and long names, additionally it takes flags that specify
-// in which mode the file should be open (see os.OpenFile for details on that), file permissions that
-// will be applied to a file and argument options.
-// Returns a pointer to os.File which will be set to opened file on success. On error the Parser.Parse
-// will return error and the pointer might be nil.
-func (o *Command) File(short string, long string, flag int, perm os.FileMode, opts *Options) *os.File {
-	var result os.File
+// IPList creates new IP address list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of net.IP values. If no argument
+// provided, then the list is empty. Takes same parameters as IP
+// Returns a pointer the list of net.IP values.
+func (o *Command) IPList(short string, long string, opts *Options) *[]net.IP {
+	result := make([]net.IP, 0)
 
 	a := &arg{
-		result:   &result,
-		sname:    short,
-		lname:    long,
-		size:     2,
-		opts:     opts,
-		unique:   true,
-		fileFlag: flag,
-		filePerm: perm,
-		argType:  File,
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: IPList,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add File: %s", err.Error()))
+		panic(fmt.Errorf("unable to add IPList: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// See func File documentation
-func (o *Command) FilePositional(flag int, perm os.FileMode, opts *Options) *os.File {
-	if opts == nil {
-		opts = &Options{}
+// IPNetList creates new network list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of net.IPNet values. If no argument
+// provided, then the list is empty. Takes same parameters as IPNet
+// Returns a pointer the list of net.IPNet values.
+func (o *Command) IPNetList(short string, long string, opts *Options) *[]net.IPNet {
+	result := make([]net.IPNet, 0)
+
+	a := &arg{
+		result:  &result,
+		sname:   short,
+		lname:   long,
+		size:    2,
+		opts:    opts,
+		unique:  false,
+		argType: IPNetList,
 	}
-	opts.positional = true
 
-	// We supply a long name for documentation and internal logic
-	name := fmt.Sprintf(positionalArgName, o.name, len(o.args))
-	return o.File("", name, flag, perm, opts)
-}
+	if err := o.addArg(a); err != nil {
+		panic(fmt.Errorf("unable to add IPNetList: %s", err.Error()))
+	}
 
-// List creates new list argument. This is the argument that is allowed to be present multiple times on CLI.
-// All appearances of this argument on CLI will be collected into the list of default type values which is strings.
-// If no argument provided, then the list is empty.
-// Takes same parameters as String.
-// Returns a pointer the list of strings.
-func (o *Command) List(short string, long string, opts *Options) *[]string {
-	return o.StringList(short, long, opts)
+	return &result
 }
 
-// StringList creates new string list argument. This is the argument that is allowed to be present multiple times on CLI.
-// All appearances of this argument on CLI will be collected into the list of strings. If no argument
-// provided, then the list is empty. Takes same parameters as String
-// Returns a pointer the list of strings.
-func (o *Command) StringList(short string, long string, opts *Options) *[]string {
+// HostPortList creates new host and port list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of string values. If no argument
+// provided, then the list is empty. Takes same parameters as HostPort
+// Returns a pointer the list of string values.
+func (o *Command) HostPortList(short string, long string, opts *Options) *[]string {
 	result := make([]string, 0)
 
 	a := &arg{
@@ -421,22 +1426,22 @@ func (o *Command) StringList(short string, long string, opts *Options) *[]string
 		size:    2,
 		opts:    opts,
 		unique:  false,
-		argType: StringList,
+		argType: HostPortList,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add StringList: %s", err.Error()))
+		panic(fmt.Errorf("unable to add HostPortList: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// IntList creates new integer list argument. This is the argument that is allowed to be present multiple times on CLI.
-// All appearances of this argument on CLI will be collected into the list of integers. If no argument
-// provided, then the list is empty. Takes same parameters as Int
-// Returns a pointer the list of integers.
-func (o *Command) IntList(short string, long string, opts *Options) *[]int {
-	result := make([]int, 0)
+// URLList creates new URL list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of url.URL values. If no argument
+// provided, then the list is empty. Takes same parameters as URL
+// Returns a pointer the list of url.URL values.
+func (o *Command) URLList(short string, long string, schemes []string, opts *Options) *[]url.URL {
+	result := make([]url.URL, 0)
 
 	a := &arg{
 		result:  &result,
@@ -445,22 +1450,23 @@ func (o *Command) IntList(short string, long string, opts *Options) *[]int {
 		size:    2,
 		opts:    opts,
 		unique:  false,
-		argType: IntList,
+		schemes: schemes,
+		argType: URLList,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add IntList: %s", err.Error()))
+		panic(fmt.Errorf("unable to add URLList: %s", err.Error()))
 	}
 
 	return &result
 }
 
-// FloatList creates new float list argument. This is the argument that is allowed to be present multiple times on CLI.
-// All appearances of this argument on CLI will be collected into the list of float64 values. If no argument
-// provided, then the list is empty. Takes same parameters as Float
-// Returns a pointer the list of float64 values.
-func (o *Command) FloatList(short string, long string, opts *Options) *[]float64 {
-	result := make([]float64, 0)
+// RegexpList creates new regular expression list argument. This is the argument that is allowed to be present multiple times on CLI.
+// All appearances of this argument on CLI will be collected into the list of compiled regular expressions. If no argument
+// provided, then the list is empty. Takes same parameters as Regexp
+// Returns a pointer the list of *regexp.Regexp values.
+func (o *Command) RegexpList(short string, long string, opts *Options) *[]*regexp.Regexp {
+	result := make([]*regexp.Regexp, 0)
 
 	a := &arg{
 		result:  &result,
@@ -469,11 +1475,11 @@ func (o *Command) FloatList(short string, long string, opts *Options) *[]float64
 		size:    2,
 		opts:    opts,
 		unique:  false,
-		argType: FloatList,
+		argType: RegexpList,
 	}
 
 	if err := o.addArg(a); err != nil {
-		panic(fmt.Errorf("unable to add FloatList: %s", err.Error()))
+		panic(fmt.Errorf("unable to add RegexpList: %s", err.Error()))
 	}
 
 	return &result
@@ -544,6 +1550,40 @@ func (o *Command) SelectorPositional(allowed []string, opts *Options) *string {
 	return o.Selector("", name, allowed, opts)
 }
 
+// AddFlagSet imports all flags defined in flag.FlagSet as arguments of the Command. It allows libraries that
+// register their options on flag.FlagSet to be part of argparse help and parsing.
+// Each flag becomes an argument with flag's name as long name, flag's usage as help message and flag's
+// default value shown as default. Values are written back to the flags through flag.FlagSet.Set during parsing,
+// so flag.FlagSet.Visit can be used to find which flags were set. Boolean flags do not take a value.
+// Flag can be present multiple times, which is handled by the flag's own flag.Value.
+func (o *Command) AddFlagSet(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) {
+		size := 2
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+			size = 1
+		}
+		_, usage := flag.UnquoteUsage(f)
+		opts := &Options{Help: usage}
+		if f.DefValue != "" {
+			opts.Default = f.DefValue
+		}
+
+		a := &arg{
+			result:  f.Value,
+			lname:   f.Name,
+			size:    size,
+			opts:    opts,
+			unique:  false,
+			flagSet: fs,
+			argType: GoFlag,
+		}
+
+		if err := o.addArg(a); err != nil {
+			panic(fmt.Errorf("unable to add flag %s: %s", f.Name, err.Error()))
+		}
+	})
+}
+
 // message2String puts msg in result string
 // done boolean indicates if result is ready to be returned
 // Accepts an interface that can be error, string or fmt.Stringer that will be prepended to a message.
@@ -672,13 +1712,23 @@ func arguments2Result(result string, arguments []*arg, maxWidth int) string {
 		argContent := "Arguments:\n\n"
 		// Get biggest padding
 		var argPadding int
+		// Get widest short name, which is a single character unless multi-character short names are allowed
+		shortWidth := 1
+		for _, argument := range arguments {
+			if argument.opts.Help == DisableDescription {
+				continue
+			}
+			if utf8.RuneCountInString(argument.sname) > shortWidth {
+				shortWidth = utf8.RuneCountInString(argument.sname)
+			}
+		}
 		// Find biggest padding
 		for _, argument := range arguments {
 			if argument.opts.Help == DisableDescription {
 				continue
 			}
-			if len(argument.lname)+9 > argPadding {
-				argPadding = len(argument.lname) + 9
+			if len(argument.lname)+8+shortWidth > argPadding {
+				argPadding = len(argument.lname) + 8 + shortWidth
 			}
 		}
 		// Now add args with padding
@@ -690,12 +1740,12 @@ func arguments2Result(result string, arguments []*arg, maxWidth int) string {
 			} else {
 				arg := "  "
 				if argument.sname != "" {
-					arg = arg + "-" + argument.sname + "  "
+					arg = arg + "-" + argument.sname + strings.Repeat(" ", shortWidth-utf8.RuneCountInString(argument.sname)) + "  "
 				} else {
-					arg = arg + "    "
+					arg = arg + strings.Repeat(" ", shortWidth+3)
 				}
 				arg = arg + "--" + argument.lname
-				arg = arg + strings.Repeat(" ", argPadding-len(arg))
+				arg = arg + strings.Repeat(" ", argPadding-utf8.RuneCountInString(arg))
 				if argument.opts != nil && argument.opts.Help != "" {
 					arg = addToLastLine(arg, argument.getHelpMessage(), maxWidth, argPadding, true)
 				}
@@ -758,6 +1808,93 @@ func (o *Command) Usage(msg interface{}) string {
 	return result
 }
 
+// FromFilePrefix allows arguments to be read from response files, which is useful when command line
+// is too long. Any argument starting with prefix (usually '@') is replaced by arguments read from the file
+// it names, so `progname @args.txt` reads arguments from `args.txt`. Relative paths are relative to current
+// working directory. Arguments in the file are separated by whitespace and quoted same as in POSIX shell,
+// `#` starts a comment until the end of the line. Response files can be nested, up to 16 levels deep.
+// Errors in response files are reported with the file name and the line number.
+func (o *Parser) FromFilePrefix(prefix rune) {
+	o.fromFilePrefix = prefix
+}
+
+// maxFileDepth - the deepest nesting of response files, see FromFilePrefix
+const maxFileDepth = 16
+
+// isFileArg - checks if argument names response file to read arguments from
+func (o *Parser) isFileArg(argument string) bool {
+	return o.fromFilePrefix != 0 && strings.HasPrefix(argument, string(o.fromFilePrefix)) &&
+		len(argument) > utf8.RuneLen(o.fromFilePrefix)
+}
+
+// expandFiles - returns copy of arguments with response files replaced by their content.
+// For every argument it also returns where it came from as `file:line`, or empty string for command line.
+func (o *Parser) expandFiles(args []string) ([]string, []string, error) {
+	expanded := make([]string, 0, len(args))
+	origins := make([]string, 0, len(args))
+	for i, arg := range args {
+		// Program name is never expanded
+		if i == 0 || !o.isFileArg(arg) {
+			expanded = append(expanded, arg)
+			origins = append(origins, "")
+			continue
+		}
+		path := arg[utf8.RuneLen(o.fromFilePrefix):]
+		if err := o.readFile(path, "", nil, &expanded, &origins); err != nil {
+			return nil, nil, err
+		}
+	}
+	return expanded, origins, nil
+}
+
+// readFile - appends arguments read from response file at path to args, expanding nested response files.
+// from is location of the argument which named the file, stack holds absolute paths of files being read.
+func (o *Parser) readFile(path, from string, stack []string, args, origins *[]string) error {
+	fail := func(err error) error {
+		if from != "" {
+			return fmt.Errorf("%s: %w", from, err)
+		}
+		return err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fail(err)
+	}
+	for _, v := range stack {
+		if v == abs {
+			return fail(fmt.Errorf("response file %s includes itself", path))
+		}
+	}
+	if len(stack) >= maxFileDepth {
+		return fail(fmt.Errorf("response files are nested deeper than %d levels", maxFileDepth))
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fail(err)
+	}
+	words, err := splitShellWords(string(data))
+	if err != nil {
+		if e, ok := err.(*shellSyntaxError); ok {
+			return fmt.Errorf("%s:%d: %s", path, e.line, e.msg)
+		}
+		return err
+	}
+	stack = append(stack, abs)
+	for _, w := range words {
+		location := fmt.Sprintf("%s:%d", path, w.line)
+		if o.isFileArg(w.value) {
+			nested := w.value[utf8.RuneLen(o.fromFilePrefix):]
+			if err := o.readFile(nested, location, stack, args, origins); err != nil {
+				return err
+			}
+			continue
+		}
+		*args = append(*args, w.value)
+		*origins = append(*origins, location)
+	}
+	return nil
+}
+
 // Parse method can be applied only on Parser. It takes a slice of strings (as in os.Args)
 // and it will process this slice as arguments of CLI (the original slice is not modified).
 // Returns error on any failure. In case of failure recommended course of action is to
@@ -766,16 +1903,22 @@ func (o *Command) Usage(msg interface{}) string {
 // In case no error returned all arguments should be safe to use. Safety of using arguments
 // before Parse operation is complete is not guaranteed.
 func (o *Parser) Parse(args []string) error {
-	subargs := make([]string, len(args))
-	copy(subargs, args)
+	subargs, origins, err := o.expandFiles(args)
+	if err != nil {
+		return err
+	}
+	o.origins = origins
 
 	result := o.parse(&subargs)
 	if result == nil {
+		o.restoreRemainder()
 		result = o.parsePositionals(&subargs)
 	}
 	unparsed := make([]string, 0)
-	for _, v := range subargs {
-		if v != "" {
+	for i, v := range subargs {
+		if origin := o.origin(i, subargs); v != "" && origin != "" {
+			unparsed = append(unparsed, fmt.Sprintf("%s (%s)", v, origin))
+		} else if v != "" {
 			unparsed = append(unparsed, v)
 		}
 	}
@@ -786,3 +1929,15 @@ func (o *Parser) Parse(args []string) error {
 
 	return result
 }
+
+// ParseString splits command line same as POSIX shell does (see SplitCommandLine) and parses resulting arguments.
+// Unlike with Parse, line does not start with program name, so it looks like `deploy --env "prod eu"`,
+// where `deploy` is a sub-command. Useful when command line comes as a single string, such as from chat bot
+// or configuration field.
+func (o *Parser) ParseString(line string) error {
+	args, err := SplitCommandLine(line)
+	if err != nil {
+		return err
+	}
+	return o.Parse(append([]string{o.name}, args...))
+}
@@ -1,13 +1,22 @@
 package argparse
 
 import (
+	"bytes"
 	"errors"
+	"flag"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp/syntax"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestInternalFunctionParse(t *testing.T) {
@@ -288,14 +297,13 @@ func TestLongFlagEqualChar(t *testing.T) {
 		return
 	}
 }
-
-func TestShortFlagEqualChar(t *testing.T) {
-	testArgs := []string{"progname", "-a=test1", "-b=2", "-c", "test3"}
+func TestLongFlagEqualCharRepeated(t *testing.T) {
+	testArgs := []string{"progname", "--tag=a", "--tag", "b", "--name=x", "--name", "y", "--level=info", "--level", "debug"}
 
 	p := NewParser("", "description")
-	flag1 := p.String("a", "flag1", nil)
-	flag2 := p.Int("b", "flag2", nil)
-	flag3 := p.String("c", "flag3", nil)
+	tags := p.StringList("", "tag", nil)
+	names := p.StringList("", "name", nil)
+	level := p.String("", "level", &Options{Duplicate: DuplicateLastWins})
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -303,49 +311,44 @@ func TestShortFlagEqualChar(t *testing.T) {
 		return
 	}
 
-	if flag1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	if !reflect.DeepEqual(*tags, []string{"a", "b"}) {
+		t.Errorf("Test %s failed. Want: [%q], got: [%q]", t.Name(), []string{"a", "b"}, *tags)
 	}
-
-	if flag2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
-		return
+	if !reflect.DeepEqual(*names, []string{"x", "y"}) {
+		t.Errorf("Test %s failed. Want: [%q], got: [%q]", t.Name(), []string{"x", "y"}, *names)
 	}
-
-	if flag3 == nil {
-		t.Errorf("Test %s failed with flag3 being nil pointer", t.Name())
-		return
+	if *level != "debug" {
+		t.Errorf("Test %s failed. Want: [debug], got: [%s]", t.Name(), *level)
 	}
+}
+func TestLongFlagEqualCharInValue(t *testing.T) {
+	testArgs := []string{"progname", "--env=KEY=value", "--opt=a=b=c"}
 
-	if *flag1 != "test1" {
-		t.Errorf("Test %s failed with flag1 being false", t.Name())
-		return
-	}
+	p := NewParser("", "description")
+	env := p.String("", "env", nil)
+	opt := p.String("", "opt", nil)
 
-	if *flag2 != 2 {
-		t.Errorf("Test %s failed with flag2 being true", t.Name())
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
 
-	if *flag3 != "test3" {
-		t.Errorf("Test %s failed with flag3 being true", t.Name())
-		return
+	if *env != "KEY=value" || *opt != "a=b=c" {
+		t.Errorf("Test %s failed. Want: [KEY=value a=b=c], got: [%s %s]", t.Name(), *env, *opt)
 	}
 }
 
-func TestFlagMultiShorthandWithParam1(t *testing.T) {
-	testArgs := []string{"progname", "-ab", "10", "-c", "-de", "11", "--ee", "12"}
-
-	testList := []int{11, 12}
+func TestLongNameAbbrev(t *testing.T) {
+	testArgs := []string{"progname", "cmd", "--verb", "--out=file.txt", "--lev", "3", "--he"}
 
 	p := NewParser("", "description")
-	flag1 := p.Flag("a", "aa", nil)
-	int2 := p.Int("b", "bb", nil)
-	flag3 := p.Flag("c", "cc", nil)
-	flag4 := p.Flag("d", "dd", nil)
-	intList5 := p.IntList("e", "ee", nil)
-	flag6 := p.Flag("f", "ff", nil)
+	p.AllowAbbrev(true)
+	verbose := p.Flag("v", "verbose", nil)
+	level := p.Int("", "level", nil)
+	cmd := p.NewCommand("cmd", "")
+	output := cmd.String("o", "output", nil)
+	he := cmd.Flag("", "he", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -353,59 +356,108 @@ func TestFlagMultiShorthandWithParam1(t *testing.T) {
 		return
 	}
 
-	if *flag1 != true {
-		t.Errorf("Test %s failed with flag1 being false", t.Name())
-	}
-
-	if *int2 != 10 {
-		t.Errorf("Test %s failed with *int2=%v being false", t.Name(), *int2)
-	}
-
-	if *flag3 != true {
-		t.Errorf("Test %s failed with flag3 being false", t.Name())
+	switch {
+	case !*verbose:
+		t.Errorf("Test %s failed with verbose being false", t.Name())
+	case *level != 3:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 3, *level)
+	case *output != "file.txt":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "file.txt", *output)
+	case !*he:
+		t.Errorf("Test %s failed with he being false", t.Name())
 	}
+}
 
-	if *flag4 != true {
-		t.Errorf("Test %s failed with flag4 being false", t.Name())
+func TestLongNameAbbrevFail(t *testing.T) {
+	type testCase struct {
+		testName, failureMessage string
+		args                     []string
+		allow                    bool
 	}
-
-	if !reflect.DeepEqual(*intList5, testList) {
-		t.Errorf("Test %s failed: expected [%v], got [%v]", t.Name(), testList, *intList5)
+	tt := []testCase{
+		{testName: "Ambiguous", allow: true, args: []string{"progname", "cmd", "--verb"}, failureMessage: "ambiguous argument --verb could match --verbatim, --verbose"},
+		{testName: "Not allowed", allow: false, args: []string{"progname", "cmd", "--verbo"}, failureMessage: "unknown arguments --verbo"},
 	}
-
-	if *flag6 != false {
-		t.Errorf("Test %s failed with flag6 being true", t.Name())
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			p := NewParser("", "description")
+			_ = p.Flag("v", "verbose", nil)
+			cmd := p.NewCommand("cmd", "")
+			_ = cmd.Flag("", "verbatim", nil)
+			p.AllowAbbrev(tc.allow)
+
+			err := p.Parse(tc.args)
+			if err == nil || err.Error() != tc.failureMessage {
+				t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.failureMessage, err)
+			}
+		})
 	}
 }
 
-func TestFlagMultiShorthandWithParamFail1(t *testing.T) {
-	testArgs := []string{"progname", "-bab", "10"}
+func TestNormalize(t *testing.T) {
+	testArgs := []string{"progname", "Deploy", "--Dry_Run", "--LEVEL=Debug", "PROD_EU"}
 
 	p := NewParser("", "description")
-	_ = p.Flag("a", "aa", nil)
-	_ = p.Int("b", "bb", nil)
+	p.Normalize(NormalizeCase, NormalizeUnderscores)
+	dryRun := p.Flag("", "dry-run", &Options{Help: "Dry run"})
+	level := p.Selector("l", "level", []string{"info", "debug"}, &Options{Help: "Log level"})
+	cmd := p.NewCommand("deploy", "")
+	env := cmd.SelectorPositional([]string{"prod-eu", "prod-us"}, nil)
 
 	err := p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed with no error", t.Name())
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
-	errExpectation := "[-b|--bb] argument: The parameter must follow"
-	if err.Error() != errExpectation {
-		t.Errorf("Test %s failed. error %q getted. %q expected", t.Name(), err.Error(), errExpectation)
+
+	switch {
+	case !cmd.Happened():
+		t.Errorf("Test %s failed with cmd not happened", t.Name())
+	case !*dryRun:
+		t.Errorf("Test %s failed with dryRun being false", t.Name())
+	case *level != "debug":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "debug", *level)
+	case *env != "prod-eu":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "prod-eu", *env)
+	}
+
+	usage := p.Usage(nil)
+	if !strings.Contains(usage, "--dry-run") || !strings.Contains(usage, "(info|debug)") {
+		t.Errorf("Test %s failed: usage does not contain canonical names:\n%s", t.Name(), usage)
+	}
+
+	failureMessage := "unable to add Flag: long name Dry_Run occurs more than once"
+	func() {
+		defer func() {
+			if r := recover(); r == nil || fmt.Sprintf("%v", r) != failureMessage {
+				t.Errorf("Test %s failed. Expected panic [%s], got [%+v]", t.Name(), failureMessage, r)
+			}
+		}()
+		cmd.Flag("", "Dry_Run", nil)
+	}()
+}
+
+func TestNormalizeDisabled(t *testing.T) {
+	p := NewParser("", "description")
+	p.Normalize(NormalizeCase)
+	p.Normalize()
+	_ = p.Flag("", "dry-run", nil)
+
+	err := p.Parse([]string{"progname", "--Dry-Run"})
+	if err == nil || err.Error() != "unknown arguments --Dry-Run" {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), "unknown arguments --Dry-Run", err)
 	}
 }
 
-func TestFlagMultiShorthand1(t *testing.T) {
-	testArgs := []string{"progname", "-abcd", "-e"}
+func TestAliases(t *testing.T) {
+	testArgs := []string{"progname", "--config-dir=/etc", "-vC", "/opt", "--verb", "--loud"}
 
+	var warnings bytes.Buffer
 	p := NewParser("", "description")
-	flag1 := p.Flag("a", "aa", nil)
-	flag2 := p.Flag("b", "bb", nil)
-	flag3 := p.Flag("c", "cc", nil)
-	flag4 := p.Flag("d", "dd", nil)
-	flag5 := p.Flag("e", "ee", nil)
-	flag6 := p.Flag("f", "ff", nil)
+	p.SetErrorWriter(&warnings)
+	p.AllowAbbrev(true)
+	config := p.StringList("c", "config-path", &Options{Aliases: []Alias{{Name: "--config-dir", Deprecated: true}, {Name: "-C", Deprecated: true}}})
+	verbose := p.FlagCounter("v", "verbose", &Options{Aliases: []Alias{{Name: "--loud"}}})
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -413,67 +465,95 @@ func TestFlagMultiShorthand1(t *testing.T) {
 		return
 	}
 
-	if *flag1 != true {
-		t.Errorf("Test %s failed with flag1 being false", t.Name())
+	switch {
+	case !reflect.DeepEqual(*config, []string{"/etc", "/opt"}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []string{"/etc", "/opt"}, *config)
+	case *verbose != 3:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 3, *verbose)
 	}
 
-	if *flag2 != true {
-		t.Errorf("Test %s failed with flag2 being false", t.Name())
+	expected := "warning: --config-dir is deprecated, use --config-path instead\n"
+	if warnings.String() != expected {
+		t.Errorf("Test %s failed. Want warnings: %q, got: %q", t.Name(), expected, warnings.String())
 	}
+}
 
-	if *flag3 != true {
-		t.Errorf("Test %s failed with flag3 being false", t.Name())
+func TestAliasesAbbrevDeprecated(t *testing.T) {
+	type testCase struct {
+		args     []string
+		expected string
 	}
-
-	if *flag4 != true {
-		t.Errorf("Test %s failed with flag4 being false", t.Name())
+	testCases := []testCase{
+		{args: []string{"progname", "--config-d=/etc"}, expected: "warning: --config-dir is deprecated, use --config-path instead\n"},
+		{args: []string{"progname", "--config-d", "/etc"}, expected: "warning: --config-dir is deprecated, use --config-path instead\n"},
+		{args: []string{"progname", "--config-p=/etc"}, expected: ""},
+		{args: []string{"progname", "--conf", "/etc"}, expected: ""},
 	}
 
-	if *flag5 != true {
-		t.Errorf("Test %s failed with flag5 being false", t.Name())
-	}
+	for _, tc := range testCases {
+		var warnings bytes.Buffer
+		p := NewParser("", "description")
+		p.SetErrorWriter(&warnings)
+		p.AllowAbbrev(true)
+		config := p.String("c", "config-path", &Options{Aliases: []Alias{{Name: "--config-dir", Deprecated: true}}})
 
-	if *flag6 != false {
-		t.Errorf("Test %s failed with flag6 being true", t.Name())
+		err := p.Parse(tc.args)
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if *config != "/etc" {
+			t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "/etc", *config)
+		}
+		if warnings.String() != tc.expected {
+			t.Errorf("Test %s failed for %v. Want warnings: %q, got: %q", t.Name(), tc.args, tc.expected, warnings.String())
+		}
 	}
 }
 
-func TestFlagCounterAddArgumentFail(t *testing.T) {
+func TestAliasesAddArgumentFail(t *testing.T) {
 	type testCase struct {
-		testName, shortArg, longArg, failureMessage string
+		testName, failureMessage string
+		aliases                  []Alias
 	}
 	tt := []testCase{
-		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add FlagCounter: short name must not exceed 1 character"},
-		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add FlagCounter: long name should be provided"},
-		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add FlagCounter: long name flag1 occurs more than once"},
-		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add FlagCounter: short name F occurs more than once"},
+		{testName: "Bad name", aliases: []Alias{{Name: "config"}}, failureMessage: "unable to add String: bad alias name [config]"},
+		{testName: "Long name twice", aliases: []Alias{{Name: "--flag"}}, failureMessage: "unable to add String: long name flag occurs more than once"},
+		{testName: "Short name twice", aliases: []Alias{{Name: "-a"}}, failureMessage: "unable to add String: short name a occurs more than once"},
+		{testName: "Alias twice", aliases: []Alias{{Name: "--old"}}, failureMessage: "unable to add String: long name old occurs more than once"},
 	}
 	for _, tc := range tt {
 		t.Run(tc.testName, func(t *testing.T) {
 			defer func() {
-				if r := recover(); r != nil {
-					rezString := fmt.Sprintf("%v", r)
-					if strings.Contains(rezString, tc.failureMessage) == false {
-						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
-					}
-				} else {
-					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
+				if r := recover(); r == nil || fmt.Sprintf("%v", r) != tc.failureMessage {
+					t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
 				}
 			}()
 			p := NewParser("", "description")
-			_ = p.FlagCounter("F", "flag1", nil)
-			_ = p.FlagCounter(tc.shortArg, tc.longArg, nil)
+			_ = p.Flag("f", "flag", &Options{Aliases: []Alias{{Name: "-a"}, {Name: "--old", Deprecated: true}}})
+			_ = p.String("s", "string", &Options{Aliases: tc.aliases})
 		})
 	}
 }
 
-func TestFlagCounterSimple1(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "--flag-arg3", "--flag-arg3"}
+func TestAliasesUsage(t *testing.T) {
+	p := NewParser("progname", "description")
+	_ = p.String("c", "config-path", &Options{Help: "Config", Aliases: []Alias{{Name: "--config-dir", Deprecated: true}, {Name: "--conf"}}})
+
+	expected := "  -c  --config-path  Config. Aliases: --conf\n"
+	if usage := p.Usage(nil); !strings.Contains(usage, expected) {
+		t.Errorf("Test %s failed: expected usage to contain:\n%s\ngot:\n%s", t.Name(), expected, usage)
+	}
+}
+
+func TestShortNameUnicode(t *testing.T) {
+	testArgs := []string{"progname", "-äb", "-üwert", "-ß", "straße"}
 
 	p := NewParser("", "description")
-	flag1 := p.FlagCounter("", "flag-arg1", nil)
-	flag2 := p.FlagCounter("", "flag-arg2", nil)
-	flag3 := p.FlagCounter("", "flag-arg3", nil)
+	flag1 := p.Flag("ä", "ae", nil)
+	flag2 := p.Flag("b", "bb", nil)
+	str1 := p.String("ü", "ue", nil)
+	str2 := p.String("ß", "sz", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -481,224 +561,443 @@ func TestFlagCounterSimple1(t *testing.T) {
 		return
 	}
 
-	if flag1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	switch {
+	case !*flag1 || !*flag2:
+		t.Errorf("Test %s failed with flags being false", t.Name())
+	case *str1 != "wert":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "wert", *str1)
+	case *str2 != "straße":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "straße", *str2)
 	}
+}
 
-	if flag2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+func TestShortNameMultiChar(t *testing.T) {
+	testArgs := []string{"progname", "cmd", "-cp", "lib.jar", "-vv", "-v", "-Xmx=4g", "-ab"}
+
+	p := NewParser("progname", "description")
+	p.MultiCharShortNames(true)
+	classPath := p.String("cp", "class-path", &Options{Help: "Class path"})
+	veryVerbose := p.Flag("vv", "very-verbose", &Options{Help: "Very verbose"})
+	verbose := p.FlagCounter("v", "verbose", &Options{Help: "Verbose"})
+	memory := p.String("Xmx", "max-memory", &Options{Help: "Memory"})
+	cmd := p.NewCommand("cmd", "")
+	ab := cmd.Flag("ab", "ab", &Options{Help: "AB"})
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
 
-	if flag3 == nil {
-		t.Errorf("Test %s failed with flag3 being nil pointer", t.Name())
-		return
+	switch {
+	case *classPath != "lib.jar":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "lib.jar", *classPath)
+	case !*veryVerbose:
+		t.Errorf("Test %s failed with veryVerbose being false", t.Name())
+	case *verbose != 1:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 1, *verbose)
+	case *memory != "4g":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "4g", *memory)
+	case !*ab:
+		t.Errorf("Test %s failed with ab being false", t.Name())
+	}
+
+	expected := "  -cp   --class-path    Class path\n  -vv   --very-verbose  Very verbose\n"
+	if usage := p.Usage(nil); !strings.Contains(usage, expected) {
+		t.Errorf("Test %s failed: expected usage to contain:\n%s\ngot:\n%s", t.Name(), expected, usage)
 	}
+}
 
-	if *flag1 != 1 {
-		t.Errorf("Test %s failed with flag1 being %d", t.Name(), *flag1)
-		return
+func TestSingleDashLongNames(t *testing.T) {
+	type testCase struct {
+		testName string
+		args     []string
+	}
+	tt := []testCase{
+		{testName: "Equals char", args: []string{"-config=x", "-verbose", "-n=3"}},
+		{testName: "Separate values", args: []string{"-config", "x", "-n", "-3"}},
+		{testName: "Double dash", args: []string{"--config", "x", "--verbose=false", "--n=4"}},
+		{testName: "Explicit bool", args: []string{"-verbose=true", "-config=a=b"}},
 	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			fs := flag.NewFlagSet("progname", flag.ContinueOnError)
+			fsConfig := fs.String("config", "", "")
+			fsVerbose := fs.Bool("verbose", false, "")
+			fsN := fs.Int("n", 0, "")
+			if err := fs.Parse(tc.args); err != nil {
+				t.Errorf("Test %s failed with flag error: %s", t.Name(), err.Error())
+				return
+			}
 
-	if *flag2 != 0 {
-		t.Errorf("Test %s failed with flag2 being %d", t.Name(), *flag2)
-		return
+			p := NewParser("progname", "description")
+			p.SingleDashLongNames(true)
+			config := p.String("c", "config", nil)
+			verbose := p.Flag("v", "verbose", nil)
+			n := p.Int("", "n", nil)
+			if err := p.Parse(append([]string{"progname"}, tc.args...)); err != nil {
+				t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+				return
+			}
+
+			if *config != *fsConfig || *verbose != *fsVerbose || *n != *fsN {
+				t.Errorf("Test %s failed. Want: [%s %v %d], got: [%s %v %d]", t.Name(), *fsConfig, *fsVerbose, *fsN, *config, *verbose, *n)
+			}
+		})
 	}
+}
 
-	if *flag3 != 2 {
-		t.Errorf("Test %s failed with flag3 being %d", t.Name(), *flag3)
+func TestSingleDashLongNamesShort(t *testing.T) {
+	testArgs := []string{"progname", "-vcfile", "-e"}
+
+	p := NewParser("progname", "description")
+	p.SingleDashLongNames(true)
+	config := p.String("c", "config", nil)
+	verbose := p.Flag("v", "verbose", nil)
+	e := p.Flag("", "e", nil)
+
+	if err := p.Parse(testArgs); err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	if *config != "file" || !*verbose || !*e {
+		t.Errorf("Test %s failed. Want: [%s %v %v], got: [%s %v %v]", t.Name(), "file", true, true, *config, *verbose, *e)
+	}
 }
-
-func TestFlagCounterSimple2(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "-f", "--flag-arg3", "-f"}
+func TestFlagEqualCharValue(t *testing.T) {
+	testArgs := []string{"progname", "--verbose=yes", "--quiet=false"}
 
 	p := NewParser("", "description")
-	flag1 := p.FlagCounter("", "flag-arg1", nil)
-	flag2 := p.FlagCounter("", "flag-arg2", nil)
-	flag3 := p.FlagCounter("f", "flag-arg3", nil)
+	verbose := p.Flag("v", "verbose", nil)
+	quiet := p.Flag("q", "quiet", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
 		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	// Value is ignored unless single dash long names are allowed
+	if !*verbose || !*quiet {
+		t.Errorf("Test %s failed. Want: [true true], got: [%v %v]", t.Name(), *verbose, *quiet)
+	}
 
-	if flag1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	p = NewParser("", "description")
+	p.SingleDashLongNames(true)
+	p.Flag("v", "verbose", nil)
+
+	err = p.Parse(testArgs[:2])
+	if err == nil || err.Error() != "[-v|--verbose] bad boolean value [yes]" {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), "[-v|--verbose] bad boolean value [yes]", err)
 	}
+}
 
-	if flag2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+func TestAddFlagSet(t *testing.T) {
+	testArgs := []string{"progname", "--log-dir", "/tmp", "--v=2", "--alsologtostderr", "--timeout", "1m", "-d"}
+
+	fs := flag.NewFlagSet("lib", flag.ContinueOnError)
+	logDir := fs.String("log-dir", "", "Write log files in this `directory`")
+	v := fs.Int("v", 0, "Log level")
+	alsoLog := fs.Bool("alsologtostderr", false, "Log to stderr as well")
+	timeout := fs.Duration("timeout", 30*time.Second, "Timeout")
+	unused := fs.String("unused", "default", "Not set")
+
+	p := NewParser("progname", "description")
+	debug := p.Flag("d", "debug", &Options{Help: "Debug"})
+	p.AddFlagSet(fs)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
 
-	if flag3 == nil {
-		t.Errorf("Test %s failed with flag3 being nil pointer", t.Name())
-		return
+	switch {
+	case *logDir != "/tmp":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "/tmp", *logDir)
+	case *v != 2:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 2, *v)
+	case !*alsoLog || !*debug:
+		t.Errorf("Test %s failed with flags being false", t.Name())
+	case *timeout != time.Minute:
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), time.Minute, *timeout)
+	case *unused != "default":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "default", *unused)
+	}
+
+	visited := 0
+	fs.Visit(func(f *flag.Flag) { visited++ })
+	if visited != 4 {
+		t.Errorf("Test %s failed. Want %d flags set, got: %d", t.Name(), 4, visited)
+	}
+
+	usage := p.Usage(nil)
+	for _, expected := range []string{"[--timeout <duration>]", "[--alsologtostderr]", "--timeout          Timeout. Default: 30s", "--log-dir          Write log files in this directory"} {
+		if !strings.Contains(usage, expected) {
+			t.Errorf("Test %s failed: expected usage to contain:\n%s\ngot:\n%s", t.Name(), expected, usage)
+		}
 	}
+}
 
-	if *flag1 != 1 {
-		t.Errorf("Test %s failed with flag1 being %d", t.Name(), *flag1)
-		return
+func TestAddFlagSetFail(t *testing.T) {
+	fs := flag.NewFlagSet("lib", flag.ContinueOnError)
+	_ = fs.Int("v", 0, "Log level")
+
+	p := NewParser("progname", "description")
+	p.AddFlagSet(fs)
+
+	err := p.Parse([]string{"progname", "--v", "high"})
+	errStr := "[--v] bad value [high]: parse error"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
 	}
 
-	if *flag2 != 0 {
-		t.Errorf("Test %s failed with flag2 being %d", t.Name(), *flag2)
-		return
+	defer func() {
+		failureMessage := "unable to add flag v: long name v occurs more than once"
+		if r := recover(); r == nil || fmt.Sprintf("%v", r) != failureMessage {
+			t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, failureMessage)
+		}
+	}()
+	p.AddFlagSet(fs)
+}
+
+func TestInterspersedFalse(t *testing.T) {
+	type testCase struct {
+		args    []string
+		signal  string
+		verbose int
+		command []string
+	}
+	testCases := []testCase{
+		{args: []string{"timeout", "-v", "-s", "KILL", "sleep", "-v", "--signal", "x"}, signal: "KILL", verbose: 1, command: []string{"sleep", "-v", "--signal", "x"}},
+		{args: []string{"timeout", "-vsTERM", "--", "-v", "5"}, signal: "TERM", verbose: 1, command: []string{"-v", "5"}},
+		{args: []string{"timeout", "--signal=HUP", "ls", "--", "-l"}, signal: "HUP", verbose: 0, command: []string{"ls", "--", "-l"}},
+		{args: []string{"timeout", "-v", "-v"}, signal: "", verbose: 2, command: []string{}},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("timeout", "description")
+		p.Interspersed(false)
+		signal := p.String("s", "signal", nil)
+		verbose := p.FlagCounter("v", "verbose", nil)
+		command := p.StringListPositional(nil)
+
+		err := p.Parse(tc.args)
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if *signal != tc.signal || *verbose != tc.verbose || !reflect.DeepEqual(*command, tc.command) {
+			t.Errorf("Test %s failed for %v. Want: %s %d %v, got: %s %d %v", t.Name(), tc.args, tc.signal, tc.verbose, tc.command, *signal, *verbose, *command)
+		}
 	}
+}
 
-	if *flag3 != 3 {
-		t.Errorf("Test %s failed with flag3 being %d", t.Name(), *flag3)
+func TestInterspersedFalseCommand(t *testing.T) {
+	testArgs := []string{"progname", "exec", "-e", "A=1", "env", "-e", "-v", "-v"}
+
+	p := NewParser("progname", "description")
+	verbose := p.Flag("v", "verbose", nil)
+	exec := p.NewCommand("exec", "Run command")
+	exec.Interspersed(false)
+	env := exec.StringList("e", "env", nil)
+	command := exec.StringListPositional(nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	if *verbose || !reflect.DeepEqual(*env, []string{"A=1"}) || !reflect.DeepEqual(*command, []string{"env", "-e", "-v", "-v"}) {
+		t.Errorf("Test %s failed. Got: %v %v %v", t.Name(), *verbose, *env, *command)
+	}
 }
 
-func TestFlagCounterMultiShorthand1(t *testing.T) {
-	testArgs := []string{"progname", "-abbcbcadaa", "-e"}
+func TestInterspersedDefault(t *testing.T) {
+	testArgs := []string{"progname", "a", "-v", "b"}
 
-	p := NewParser("", "description")
-	flag1 := p.FlagCounter("a", "aa", nil)
-	flag2 := p.FlagCounter("b", "bb", nil)
-	flag3 := p.FlagCounter("c", "cc", nil)
-	flag4 := p.FlagCounter("d", "dd", nil)
-	flag5 := p.FlagCounter("e", "ee", nil)
-	flag6 := p.FlagCounter("f", "ff", nil)
+	p := NewParser("progname", "description")
+	verbose := p.Flag("v", "verbose", nil)
+	rest := p.StringListPositional(nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
 		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	if !*verbose || !reflect.DeepEqual(*rest, []string{"a", "b"}) {
+		t.Errorf("Test %s failed. Got: %v %v", t.Name(), *verbose, *rest)
+	}
+}
 
-	if *flag1 != 4 {
-		t.Errorf("Test %s failed with flag1 being %d", t.Name(), *flag1)
+func writeArgsFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Test %s failed to write %s: %s", t.Name(), path, err.Error())
 	}
+	return path
+}
 
-	if *flag2 != 3 {
-		t.Errorf("Test %s failed with flag2 being %d", t.Name(), *flag2)
+func TestFromFilePrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "argparse")
+	if err != nil {
+		t.Fatalf("Test %s failed with error: %s", t.Name(), err.Error())
 	}
+	defer os.RemoveAll(dir)
 
-	if *flag3 != 2 {
-		t.Errorf("Test %s failed with flag3 being %d", t.Name(), *flag3)
-	}
+	nested := writeArgsFile(t, dir, "nested.txt", "--tag x\\ y --tag 'a \"b\"'\n")
+	args := writeArgsFile(t, dir, "args.txt", "# Build arguments\n--name \"John \\\"Smith\\\"\" -i 5 # count\n@"+nested+"\n\"multi\nline\"\n")
+	testArgs := []string{"progname", "@" + args, "--tag", "z", "@"}
 
-	if *flag4 != 1 {
-		t.Errorf("Test %s failed with flag4 being %d", t.Name(), *flag4)
-	}
+	p := NewParser("progname", "description")
+	p.FromFilePrefix('@')
+	name := p.String("n", "name", nil)
+	count := p.Int("i", "int", nil)
+	tags := p.StringList("t", "tag", nil)
+	rest := p.StringListPositional(nil)
 
-	if *flag5 != 1 {
-		t.Errorf("Test %s failed with flag5 being %d", t.Name(), *flag5)
+	err = p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
 	}
-
-	if *flag6 != 0 {
-		t.Errorf("Test %s failed with flag6 being %d", t.Name(), *flag6)
+	if *name != "John \"Smith\"" {
+		t.Errorf("Test %s failed. Want: %q, got: %q", t.Name(), "John \"Smith\"", *name)
 	}
-}
-
-func TestFailDuplicate(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "-f"}
-
-	p := NewParser("", "description")
-	_ = p.Flag("f", "flag-arg1", nil)
-	_ = p.Flag("", "flag-arg2", nil)
-
-	err := p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed with. Duplicate flag use not detected", t.Name())
-		return
+	if *count != 5 {
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 5, *count)
 	}
-
-	testArgs = []string{"progname", "--flag-arg2", "-ff"}
-
-	p = NewParser("", "description")
-	_ = p.Flag("f", "flag-arg1", nil)
-	_ = p.Flag("", "flag-arg2", nil)
-
-	err = p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed with. Duplicate flag use not detected", t.Name())
-		return
+	if !reflect.DeepEqual(*tags, []string{"x y", "a \"b\"", "z"}) {
+		t.Errorf("Test %s failed. Got: %q", t.Name(), *tags)
 	}
+	if !reflect.DeepEqual(*rest, []string{"multi\nline", "@"}) {
+		t.Errorf("Test %s failed. Got: %q", t.Name(), *rest)
+	}
+}
 
-	testArgs = []string{"progname", "--flag-arg2", "-f"}
-
-	p = NewParser("", "description")
-	_ = p.Flag("f", "flag-arg1", nil)
-	_ = p.Flag("", "flag-arg2", nil)
+func TestFromFilePrefixDisabled(t *testing.T) {
+	p := NewParser("progname", "description")
+	s := p.StringPositional(nil)
 
-	err = p.Parse(testArgs)
+	err := p.Parse([]string{"progname", "@user"})
 	if err != nil {
-		t.Errorf("Test %s failed with. Fake duplicate flag detected", t.Name())
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	if *s != "@user" {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "@user", *s)
+	}
 }
 
-func TestFailCaseSensitive(t *testing.T) {
-	testArgs := []string{"progname", "-F"}
+func TestFromFilePrefixFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "argparse")
+	if err != nil {
+		t.Fatalf("Test %s failed with error: %s", t.Name(), err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	unterminated := writeArgsFile(t, dir, "unterminated.txt", "--name\n'John\nSmith\n")
+	unknown := writeArgsFile(t, dir, "unknown.txt", "--name John\n\n--bogus\n")
+	cycleA := filepath.Join(dir, "cycle-a.txt")
+	cycleB := writeArgsFile(t, dir, "cycle-b.txt", "--name x\n@"+cycleA)
+	writeArgsFile(t, dir, "cycle-a.txt", "@"+cycleB)
+	deep := writeArgsFile(t, dir, "deep-0.txt", "--name x")
+	for i := 1; i <= maxFileDepth; i++ {
+		deep = writeArgsFile(t, dir, fmt.Sprintf("deep-%d.txt", i), "@"+deep)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+	missingNested := writeArgsFile(t, dir, "missing-nested.txt", "\n@"+missing)
+	badValue := writeArgsFile(t, dir, "bad-value.txt", "--name x\n--count\nabc\n")
+	invalid := writeArgsFile(t, dir, "invalid.txt", "--name x --count=13\n")
+	emptyValue := writeArgsFile(t, dir, "empty-value.txt", "--name x\n--count=\n")
+	trailing := writeArgsFile(t, dir, "trailing.txt", "--name x\n--count\n")
+	ambiguous := writeArgsFile(t, dir, "ambiguous.txt", "--name x\n--ver\n")
+	ambiguousEqual := writeArgsFile(t, dir, "ambiguous-equal.txt", "--name x\n--ver=1\n")
 
-	p := NewParser("", "description")
-	_ = p.Flag("f", "flag", &Options{Required: true})
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "@" + unterminated}, errStr: unterminated + ":2: unterminated single quote"},
+		{args: []string{"progname", "--bogus2", "@" + unknown}, errStr: "unknown arguments --bogus2 --bogus (" + unknown + ":3)"},
+		{args: []string{"progname", "@" + cycleA}, errStr: cycleB + ":2: response file " + cycleA + " includes itself"},
+		{args: []string{"progname", "@" + deep}, errStr: filepath.Join(dir, "deep-1.txt") + ":1: response files are nested deeper than 16 levels"},
+		{args: []string{"progname", "@" + missingNested}, errStr: missingNested + ":2: open " + missing + ": no such file or directory"},
+		{args: []string{"progname", "@" + badValue}, errStr: badValue + ":3: [-c|--count] bad integer value [abc]"},
+		{args: []string{"progname", "@" + invalid}, errStr: invalid + ":1: [-c|--count] unlucky number"},
+		{args: []string{"progname", "--count", "abc", "@" + invalid}, errStr: "[-c|--count] bad integer value [abc]"},
+		{args: []string{"progname", "@" + emptyValue}, errStr: emptyValue + ":2: not enough arguments for -c|--count"},
+		{args: []string{"progname", "@" + trailing}, errStr: trailing + ":2: not enough arguments for -c|--count"},
+		{args: []string{"progname", "@" + ambiguous}, errStr: ambiguous + ":2: ambiguous argument --ver could match --verbose, --version"},
+		{args: []string{"progname", "@" + ambiguousEqual}, errStr: ambiguousEqual + ":2: ambiguous argument --ver could match --verbose, --version"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("progname", "description")
+		p.FromFilePrefix('@')
+		p.AllowAbbrev(true)
+		p.String("n", "name", nil)
+		p.Flag("", "verbose", nil)
+		p.Flag("", "version", nil)
+		p.Int("c", "count", &Options{Validate: func(args []string) error {
+			if len(args) > 0 && args[0] == "13" {
+				return errors.New("unlucky number")
+			}
+			return nil
+		}})
 
-	err := p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed with. Sees -F as -f", t.Name())
-		return
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
 	}
 }
 
-func TestFailExcessiveArguments(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "whatever"}
-
-	p := NewParser("", "description")
-	_ = p.Flag("f", "flag-arg1", nil)
-	_ = p.Flag("", "flag-arg2", nil)
+func TestParseString(t *testing.T) {
+	p := NewParser("progname", "description")
+	env := p.String("e", "env", nil)
+	tags := p.StringList("t", "tag", nil)
 
-	err := p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed with. Excessive argument not detected", t.Name())
+	err := p.ParseString(`--env "prod eu" --tag 'a b' -t c\ d`)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
-}
+	if *env != "prod eu" || !reflect.DeepEqual(*tags, []string{"a b", "c d"}) {
+		t.Errorf("Test %s failed. Got: %q %q", t.Name(), *env, *tags)
+	}
 
-func TestStringAddArgumentFail(t *testing.T) {
-	type testCase struct {
-		testName, shortArg, longArg, failureMessage string
+	p = NewParser("progname", "description")
+	p.String("e", "env", nil)
+	err = p.ParseString(`--env "prod eu`)
+	errStr := "unterminated double quote"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
 	}
-	tt := []testCase{
-		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add String: short name must not exceed 1 character"},
-		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add String: long name should be provided"},
-		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add String: long name flag1 occurs more than once"},
-		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add String: short name F occurs more than once"},
+}
+func TestParseStringCommand(t *testing.T) {
+	p := NewParser("progname", "description")
+	deploy := p.NewCommand("deploy", "")
+	env := deploy.String("e", "env", nil)
+	p.NewCommand("rollback", "")
+
+	err := p.ParseString(`deploy --env "prod eu"`)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
 	}
-	for _, tc := range tt {
-		t.Run(tc.testName, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					rezString := fmt.Sprintf("%v", r)
-					if strings.Contains(rezString, tc.failureMessage) == false {
-						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
-					}
-				} else {
-					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
-				}
-			}()
-			p := NewParser("", "description")
-			_ = p.String("F", "flag1", nil)
-			_ = p.String(tc.shortArg, tc.longArg, nil)
-		})
+	if !deploy.Happened() || *env != "prod eu" {
+		t.Errorf("Test %s failed. Want: [true prod eu], got: [%v %s]", t.Name(), deploy.Happened(), *env)
 	}
 }
 
-func TestStringSimple1(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "test"}
+func TestShortFlagEqualChar(t *testing.T) {
+	testArgs := []string{"progname", "-a=test1", "-b=2", "-c", "test3"}
 
 	p := NewParser("", "description")
-	s1 := p.String("f", "flag-arg1", nil)
-	s2 := p.String("", "flag-arg2", nil)
+	flag1 := p.String("a", "flag1", nil)
+	flag2 := p.Int("b", "flag2", nil)
+	flag3 := p.String("c", "flag3", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -706,60 +1005,49 @@ func TestStringSimple1(t *testing.T) {
 		return
 	}
 
-	if s1 == nil {
+	if flag1 == nil {
 		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
 		return
 	}
 
-	if s2 == nil {
+	if flag2 == nil {
 		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
 		return
 	}
 
-	if *s1 != "test" {
-		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "test", *s1)
+	if flag3 == nil {
+		t.Errorf("Test %s failed with flag3 being nil pointer", t.Name())
 		return
 	}
 
-	if *s2 != "" {
-		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "\"\"", *s1)
+	if *flag1 != "test1" {
+		t.Errorf("Test %s failed with flag1 being false", t.Name())
 		return
 	}
 
-	if args := p.GetArgs(); args == nil {
-		t.Errorf("Test %s failed with args empty", t.Name())
-	} else if len(args) != 3 { // our two + help
-		t.Errorf("Test %s failed with wrong len", t.Name())
-	} else {
-		got := 0
-		for _, arg := range args {
-			switch arg.GetLname() {
-			case "flag-arg1":
-				if *arg.GetResult().(*string) != *s1 {
-					t.Errorf("Test %s failed with wrong arg value", t.Name())
-				}
-				got += 3
-			case "flag-arg2":
-				if *arg.GetResult().(*string) != "" {
-					t.Errorf("Test %s failed with non-nil result", t.Name())
-				}
-				got += 5
-			case "help":
-				got += 11
-			}
-		}
-		if got != 19 {
-			t.Errorf("Test %s failed with wrong args found", t.Name())
-		}
+	if *flag2 != 2 {
+		t.Errorf("Test %s failed with flag2 being true", t.Name())
+		return
+	}
+
+	if *flag3 != "test3" {
+		t.Errorf("Test %s failed with flag3 being true", t.Name())
+		return
 	}
 }
 
-func TestStringSimple2(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "test"}
+func TestFlagMultiShorthandWithParam1(t *testing.T) {
+	testArgs := []string{"progname", "-ab", "10", "-c", "-de", "11", "--ee", "12"}
+
+	testList := []int{11, 12}
 
 	p := NewParser("", "description")
-	s1 := p.String("f", "flag-arg1", nil)
-	s2 := p.String("", "flag-arg2", nil)
+	flag1 := p.Flag("a", "aa", nil)
+	int2 := p.Int("b", "bb", nil)
+	flag3 := p.Flag("c", "cc", nil)
+	flag4 := p.Flag("d", "dd", nil)
+	intList5 := p.IntList("e", "ee", nil)
+	flag6 := p.Flag("f", "ff", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -767,63 +1055,60 @@ func TestStringSimple2(t *testing.T) {
 		return
 	}
 
-	if s1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	if *flag1 != true {
+		t.Errorf("Test %s failed with flag1 being false", t.Name())
 	}
 
-	if s2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
-		return
+	if *int2 != 10 {
+		t.Errorf("Test %s failed with *int2=%v being false", t.Name(), *int2)
 	}
 
-	if *s1 != "test" {
-		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "test", *s1)
-		return
+	if *flag3 != true {
+		t.Errorf("Test %s failed with flag3 being false", t.Name())
 	}
 
-	if *s2 != "" {
-		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "\"\"", *s1)
-		return
+	if *flag4 != true {
+		t.Errorf("Test %s failed with flag4 being false", t.Name())
 	}
-}
 
-func TestIntAddArgumentFail(t *testing.T) {
-	type testCase struct {
-		testName, shortArg, longArg, failureMessage string
+	if !reflect.DeepEqual(*intList5, testList) {
+		t.Errorf("Test %s failed: expected [%v], got [%v]", t.Name(), testList, *intList5)
 	}
-	tt := []testCase{
-		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add Int: short name must not exceed 1 character"},
-		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add Int: long name should be provided"},
-		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add Int: long name flag1 occurs more than once"},
-		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add Int: short name F occurs more than once"},
+
+	if *flag6 != false {
+		t.Errorf("Test %s failed with flag6 being true", t.Name())
 	}
-	for _, tc := range tt {
-		t.Run(tc.testName, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					rezString := fmt.Sprintf("%v", r)
-					if strings.Contains(rezString, tc.failureMessage) == false {
-						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
-					}
-				} else {
-					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
-				}
-			}()
-			p := NewParser("", "description")
-			_ = p.Int("F", "flag1", nil)
-			_ = p.Int(tc.shortArg, tc.longArg, nil)
-		})
+}
+
+func TestFlagMultiShorthandWithParamFail1(t *testing.T) {
+	testArgs := []string{"progname", "-bab", "10"}
+
+	p := NewParser("", "description")
+	_ = p.Flag("a", "aa", nil)
+	_ = p.Int("b", "bb", nil)
+
+	err := p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed with no error", t.Name())
+		return
+	}
+	errExpectation := "[-b|--bb] bad integer value [ab]"
+	if err.Error() != errExpectation {
+		t.Errorf("Test %s failed. error %q getted. %q expected", t.Name(), err.Error(), errExpectation)
 	}
 }
 
-func TestIntSimple1(t *testing.T) {
-	val := 5150
-	testArgs := []string{"progname", "--flag-arg1", strconv.Itoa(val)}
+func TestShortAttachedValue(t *testing.T) {
+	testArgs := []string{"progname", "-ofile.txt", "-n5", "-xvf", "archive.tar", "-j=8", "-lf"}
 
 	p := NewParser("", "description")
-	i1 := p.Int("f", "flag-arg1", nil)
-	i2 := p.Int("", "flag-arg2", nil)
+	output := p.String("o", "output", nil)
+	number := p.Int("n", "number", nil)
+	extract := p.Flag("x", "extract", nil)
+	verbose := p.FlagCounter("v", "verbose", nil)
+	file := p.String("f", "file", nil)
+	jobs := p.Int("j", "jobs", nil)
+	list := p.StringList("l", "list", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -831,34 +1116,37 @@ func TestIntSimple1(t *testing.T) {
 		return
 	}
 
-	if i1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	if *output != "file.txt" {
+		t.Errorf("Test %s failed: expected [%s], got [%s]", t.Name(), "file.txt", *output)
 	}
-
-	if i2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
-		return
+	if *number != 5 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), 5, *number)
 	}
-
-	if *i1 != val {
-		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), val, *i1)
-		return
+	if *extract != true {
+		t.Errorf("Test %s failed with extract being false", t.Name())
 	}
-
-	if *i2 != 0 {
-		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 0, *i1)
-		return
+	// "v" inside of "file.txt" value must not be counted
+	if *verbose != 1 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), 1, *verbose)
+	}
+	if *file != "archive.tar" {
+		t.Errorf("Test %s failed: expected [%s], got [%s]", t.Name(), "archive.tar", *file)
+	}
+	if *jobs != 8 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), 8, *jobs)
+	}
+	if !reflect.DeepEqual(*list, []string{"f"}) {
+		t.Errorf("Test %s failed: expected [%v], got [%v]", t.Name(), []string{"f"}, *list)
 	}
 }
 
-func TestIntSimple2(t *testing.T) {
-	val := 5150
-	testArgs := []string{"progname", "--flag-arg1", strconv.Itoa(val)}
+func TestShortAttachedValueSubCommand(t *testing.T) {
+	testArgs := []string{"progname", "cmd", "-vofile", "-ovfile"}
 
 	p := NewParser("", "description")
-	i1 := p.Int("f", "flag-arg1", nil)
-	i2 := p.Int("", "flag-arg2", nil)
+	verbose := p.FlagCounter("v", "verbose", nil)
+	cmd := p.NewCommand("cmd", "")
+	output := cmd.StringList("o", "output", nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -866,108 +1154,221 @@ func TestIntSimple2(t *testing.T) {
 		return
 	}
 
-	if i1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	if *verbose != 1 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), 1, *verbose)
 	}
+	if !reflect.DeepEqual(*output, []string{"file", "vfile"}) {
+		t.Errorf("Test %s failed: expected [%v], got [%v]", t.Name(), []string{"file", "vfile"}, *output)
+	}
+}
 
-	if i2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
-		return
+func TestShortAttachedValueEmptyFail(t *testing.T) {
+	testArgs := []string{"progname", "-n="}
+
+	p := NewParser("", "description")
+	_ = p.Int("n", "number", nil)
+
+	err := p.Parse(testArgs)
+	errStr := "not enough arguments for -n|--number"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
 	}
+}
 
-	if *i1 != val {
-		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), val, *i1)
+func TestNegativeNumbersAuto(t *testing.T) {
+	testArgs := []string{"progname", "--offset", "-5", "-3", "--scale", "-.5", "-f"}
+
+	p := NewParser("", "description")
+	five := p.Flag("f", "five", nil)
+	scale := p.Float("s", "scale", nil)
+	offset := p.Int("o", "offset", nil)
+	pos := p.IntPositional(nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
 
-	if *i2 != 0 {
-		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 0, *i1)
-		return
+	if *offset != -5 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), -5, *offset)
+	}
+	if *pos != -3 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), -3, *pos)
+	}
+	if *scale != -0.5 {
+		t.Errorf("Test %s failed: expected [%f], got [%f]", t.Name(), -0.5, *scale)
+	}
+	if *five != true {
+		t.Errorf("Test %s failed with five being false", t.Name())
 	}
 }
 
-func TestIntFailSimple1(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1", "string"}
+func TestNegativeNumbersAutoDigitShortName(t *testing.T) {
+	testArgs := []string{"progname", "cmd", "-1", "-2"}
 
 	p := NewParser("", "description")
-	i1 := p.Int("f", "flag-arg1", nil)
+	one := p.Flag("1", "one", nil)
+	cmd := p.NewCommand("cmd", "")
+	pos1 := cmd.IntPositional(nil)
+	pos2 := cmd.IntPositional(nil)
 
 	err := p.Parse(testArgs)
-	errStr := "[-f|--flag-arg1] bad integer value [string]"
-	if err == nil || err.Error() != errStr {
-		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
 
-	if i1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	if *one != true {
+		t.Errorf("Test %s failed with one being false", t.Name())
+	}
+	if *pos1 != -2 || *pos2 != 0 {
+		t.Errorf("Test %s failed: expected [%d %d], got [%d %d]", t.Name(), -2, 0, *pos1, *pos2)
 	}
+}
 
-	if *i1 != 0 {
-		t.Errorf("Test %s failed. Want: [0], got: [%d]", t.Name(), *i1)
+func TestNegativeNumbersAsValues(t *testing.T) {
+	testArgs := []string{"progname", "cmd", "-1", "-15"}
+
+	p := NewParser("", "description")
+	one := p.Flag("1", "one", nil)
+	five := p.FlagCounter("5", "five", nil)
+	p.NegativeNumbers(NegativeNumbersAsValues)
+	cmd := p.NewCommand("cmd", "")
+	pos1 := cmd.IntPositional(nil)
+	pos2 := cmd.IntPositional(nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+
+	if *one != false || *five != 0 {
+		t.Errorf("Test %s failed: negative numbers parsed as flags", t.Name())
+	}
+	if *pos1 != -1 || *pos2 != -15 {
+		t.Errorf("Test %s failed: expected [%d %d], got [%d %d]", t.Name(), -1, -15, *pos1, *pos2)
+	}
 }
 
-func TestEqualIntFailSimple1(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1=string"}
+func TestNegativeNumbersAsOptions(t *testing.T) {
+	testArgs := []string{"progname", "-15"}
 
 	p := NewParser("", "description")
-	i1 := p.Int("f", "flag-arg1", nil)
+	five := p.FlagCounter("5", "five", nil)
+	p.NegativeNumbers(NegativeNumbersAsOptions)
 
 	err := p.Parse(testArgs)
-	errStr := "[-f|--flag-arg1] bad integer value [string]"
-	if err == nil || err.Error() != errStr {
-		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	if err == nil || err.Error() != "unknown arguments -1" {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), "unknown arguments -1", err)
 		return
 	}
+	if *five != 1 {
+		t.Errorf("Test %s failed: expected [%d], got [%d]", t.Name(), 1, *five)
+	}
+}
 
-	if i1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+func TestNegativeDurationValues(t *testing.T) {
+	fixed := time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return fixed }
+	defer func() { timeNow = time.Now }()
+
+	modes := []NegativeNumbersMode{NegativeNumbersAuto, NegativeNumbersAsValues, NegativeNumbersAsOptions}
+	for _, mode := range modes {
+		p := NewParser("", "description")
+		p.NegativeNumbers(mode)
+		verbose := p.Flag("v", "verbose", nil)
+		since := p.Time("s", "since", nil, nil)
+		offset := p.Duration("o", "offset", nil)
+		delays := p.DurationList("d", "delays", &Options{NArgs: "+"})
+
+		err := p.Parse([]string{"progname", "--since", "-2h", "-o", "-1h30m", "-d", "-1h", "-2m", "-v"})
+		if err != nil {
+			t.Errorf("Test %s failed for mode %d with error: %s", t.Name(), mode, err.Error())
+			continue
+		}
+
+		switch {
+		case !since.Equal(fixed.Add(-2 * time.Hour)):
+			t.Errorf("Test %s failed for mode %d. Want: [%v], got: [%v]", t.Name(), mode, fixed.Add(-2*time.Hour), *since)
+		case *offset != -90*time.Minute:
+			t.Errorf("Test %s failed for mode %d. Want: [%v], got: [%v]", t.Name(), mode, -90*time.Minute, *offset)
+		case !reflect.DeepEqual(*delays, []time.Duration{-time.Hour, -2 * time.Minute}):
+			t.Errorf("Test %s failed for mode %d. Want: %v, got: %v", t.Name(), mode, []time.Duration{-time.Hour, -2 * time.Minute}, *delays)
+		case !*verbose:
+			t.Errorf("Test %s failed for mode %d with verbose being false", t.Name(), mode)
+		}
 	}
+}
 
-	if *i1 != 0 {
-		t.Errorf("Test %s failed. Want: [0], got: [%d]", t.Name(), *i1)
+func TestNegativeDurationNotValue(t *testing.T) {
+	p := NewParser("", "description")
+	verbose := p.Flag("v", "verbose", nil)
+	pos := p.StringPositional(nil)
+
+	err := p.Parse([]string{"progname", "-2v"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	// Only Duration and Time arguments take values that look like negative durations
+	if !*verbose || *pos != "-2" {
+		t.Errorf("Test %s failed. Want: [true -2], got: [%t %s]", t.Name(), *verbose, *pos)
+	}
 }
 
-func TestEqualNoValFailSimple(t *testing.T) {
-	testArgs := []string{"progname", "--flag-arg1="}
+func TestFlagMultiShorthand1(t *testing.T) {
+	testArgs := []string{"progname", "-abcd", "-e"}
 
 	p := NewParser("", "description")
-	i1 := p.Int("f", "flag-arg1", nil)
+	flag1 := p.Flag("a", "aa", nil)
+	flag2 := p.Flag("b", "bb", nil)
+	flag3 := p.Flag("c", "cc", nil)
+	flag4 := p.Flag("d", "dd", nil)
+	flag5 := p.Flag("e", "ee", nil)
+	flag6 := p.Flag("f", "ff", nil)
 
 	err := p.Parse(testArgs)
-	errStr := "not enough arguments for -f|--flag-arg1"
-	if err == nil || err.Error() != errStr {
-		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
 
-	if i1 == nil {
-		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
-		return
+	if *flag1 != true {
+		t.Errorf("Test %s failed with flag1 being false", t.Name())
 	}
 
-	if *i1 != 0 {
-		t.Errorf("Test %s failed. Want: [0], got: [%d]", t.Name(), *i1)
-		return
+	if *flag2 != true {
+		t.Errorf("Test %s failed with flag2 being false", t.Name())
+	}
+
+	if *flag3 != true {
+		t.Errorf("Test %s failed with flag3 being false", t.Name())
+	}
+
+	if *flag4 != true {
+		t.Errorf("Test %s failed with flag4 being false", t.Name())
+	}
+
+	if *flag5 != true {
+		t.Errorf("Test %s failed with flag5 being false", t.Name())
+	}
+
+	if *flag6 != false {
+		t.Errorf("Test %s failed with flag6 being true", t.Name())
 	}
 }
 
-func TestFileAddArgumentFail(t *testing.T) {
+func TestFlagCounterAddArgumentFail(t *testing.T) {
 	type testCase struct {
 		testName, shortArg, longArg, failureMessage string
 	}
 	tt := []testCase{
-		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add File: short name must not exceed 1 character"},
-		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add File: long name should be provided"},
-		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add File: long name flag1 occurs more than once"},
-		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add File: short name F occurs more than once"},
+		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add FlagCounter: short name must not exceed 1 character"},
+		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add FlagCounter: long name should be provided"},
+		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add FlagCounter: long name flag1 occurs more than once"},
+		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add FlagCounter: short name F occurs more than once"},
 	}
 	for _, tc := range tt {
 		t.Run(tc.testName, func(t *testing.T) {
@@ -982,112 +1383,783 @@ func TestFileAddArgumentFail(t *testing.T) {
 				}
 			}()
 			p := NewParser("", "description")
-			_ = p.File("F", "flag1", os.O_RDWR, 0666, nil)
-			_ = p.File(tc.shortArg, tc.longArg, os.O_RDWR, 0666, nil)
+			_ = p.FlagCounter("F", "flag1", nil)
+			_ = p.FlagCounter(tc.shortArg, tc.longArg, nil)
 		})
 	}
 }
 
-func TestFileSimple1(t *testing.T) {
-	// Test file location
-	fpath := "./test.tmp"
-	// Create test file
-	f, err := os.Create(fpath)
-	if err != nil {
-		t.Error(err)
-		return
-	}
-	f.Close()
-	defer os.Remove(fpath)
-
-	testArgs := []string{"progname", "-f", fpath}
-
-	p := NewParser("", "")
+func TestFlagCounterSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "--flag-arg3", "--flag-arg3"}
 
-	file1 := p.File("f", "file", os.O_RDWR, 0666, &Options{Default: "./non-existent-file.tmp"})
+	p := NewParser("", "description")
+	flag1 := p.FlagCounter("", "flag-arg1", nil)
+	flag2 := p.FlagCounter("", "flag-arg2", nil)
+	flag3 := p.FlagCounter("", "flag-arg3", nil)
 
-	err = p.Parse(testArgs)
+	err := p.Parse(testArgs)
 	if err != nil {
 		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
-	if file1 == nil {
-		t.Errorf("Test %s failed with file1 being nil pointer", t.Name())
+
+	if flag1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
 		return
 	}
 
-	defer file1.Close()
-
-	testString := "Test"
-	recSlice := make([]byte, 4)
-	_, err = file1.WriteString(testString)
-	if err != nil {
-		t.Errorf("Test %s write operation failed with error: %s", t.Name(), err.Error())
+	if flag2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
 		return
 	}
-	file1.Seek(0, 0)
-	n, err := file1.Read(recSlice)
-	if err != nil {
-		t.Errorf("Test %s read operation failed with error: %s", t.Name(), err.Error())
+
+	if flag3 == nil {
+		t.Errorf("Test %s failed with flag3 being nil pointer", t.Name())
 		return
 	}
-	if n != 4 || string(recSlice) != testString {
-		t.Errorf("Test %s failed on read operation", t.Name())
+
+	if *flag1 != 1 {
+		t.Errorf("Test %s failed with flag1 being %d", t.Name(), *flag1)
 		return
 	}
-}
 
-func TestFileSimpleFail1(t *testing.T) {
-	// Not existing test file location
-	fpath := "./non-existent-file.tmp"
-	// To be shure there is no fake file
-	if _, err := os.Stat(fpath); os.IsNotExist(err) != true {
-		//we could remove it, but what if it's important
-		t.Errorf("Test %s failed. There is \"%s\" file in module directory, which must not exists for test purposes", t.Name(), fpath)
+	if *flag2 != 0 {
+		t.Errorf("Test %s failed with flag2 being %d", t.Name(), *flag2)
 		return
 	}
 
-	testArgs := []string{"progname"}
+	if *flag3 != 2 {
+		t.Errorf("Test %s failed with flag3 being %d", t.Name(), *flag3)
+		return
+	}
+}
 
-	p := NewParser("", "")
+func TestFlagCounterSimple2(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "-f", "--flag-arg3", "-f"}
 
-	_ = p.File("f", "file", os.O_RDWR, 0666, &Options{Default: fpath})
+	p := NewParser("", "description")
+	flag1 := p.FlagCounter("", "flag-arg1", nil)
+	flag2 := p.FlagCounter("", "flag-arg2", nil)
+	flag3 := p.FlagCounter("f", "flag-arg3", nil)
 
 	err := p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed. Parsing should fail.", t.Name())
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
 	}
-	err, ok := err.(*os.PathError)
 
-	if ok == false {
-		t.Errorf("Test %s failed with error: %s, that is not of *os.PathError type", t.Name(), err.Error())
+	if flag1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
 	}
-}
 
-func TestFileSimpleFail2(t *testing.T) {
-	// Not existing test file location
-	fpath := "./non-existent-file.tmp"
-	// To be shure there is no fake file
-	if _, err := os.Stat(fpath); os.IsNotExist(err) != true {
-		//we could remove it, but what if it's important
-		t.Errorf("Test %s failed. There is \"%s\" file in module directory, which must not exists for test purposes", t.Name(), fpath)
+	if flag2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
 		return
 	}
 
-	testArgs := []string{"progname", "-f", fpath}
-
-	p := NewParser("", "")
-
-	_ = p.File("f", "file", os.O_RDWR, 0666, nil)
+	if flag3 == nil {
+		t.Errorf("Test %s failed with flag3 being nil pointer", t.Name())
+		return
+	}
 
-	err := p.Parse(testArgs)
-	if err == nil {
-		t.Errorf("Test %s failed. Parsing should fail.", t.Name())
+	if *flag1 != 1 {
+		t.Errorf("Test %s failed with flag1 being %d", t.Name(), *flag1)
 		return
 	}
-	err, ok := err.(*os.PathError)
 
-	if ok == false {
+	if *flag2 != 0 {
+		t.Errorf("Test %s failed with flag2 being %d", t.Name(), *flag2)
+		return
+	}
+
+	if *flag3 != 3 {
+		t.Errorf("Test %s failed with flag3 being %d", t.Name(), *flag3)
+		return
+	}
+}
+
+func TestFlagCounterMultiShorthand1(t *testing.T) {
+	testArgs := []string{"progname", "-abbcbcadaa", "-e"}
+
+	p := NewParser("", "description")
+	flag1 := p.FlagCounter("a", "aa", nil)
+	flag2 := p.FlagCounter("b", "bb", nil)
+	flag3 := p.FlagCounter("c", "cc", nil)
+	flag4 := p.FlagCounter("d", "dd", nil)
+	flag5 := p.FlagCounter("e", "ee", nil)
+	flag6 := p.FlagCounter("f", "ff", nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	if *flag1 != 4 {
+		t.Errorf("Test %s failed with flag1 being %d", t.Name(), *flag1)
+	}
+
+	if *flag2 != 3 {
+		t.Errorf("Test %s failed with flag2 being %d", t.Name(), *flag2)
+	}
+
+	if *flag3 != 2 {
+		t.Errorf("Test %s failed with flag3 being %d", t.Name(), *flag3)
+	}
+
+	if *flag4 != 1 {
+		t.Errorf("Test %s failed with flag4 being %d", t.Name(), *flag4)
+	}
+
+	if *flag5 != 1 {
+		t.Errorf("Test %s failed with flag5 being %d", t.Name(), *flag5)
+	}
+
+	if *flag6 != 0 {
+		t.Errorf("Test %s failed with flag6 being %d", t.Name(), *flag6)
+	}
+}
+
+func TestFailDuplicate(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "-f"}
+
+	p := NewParser("", "description")
+	_ = p.Flag("f", "flag-arg1", nil)
+	_ = p.Flag("", "flag-arg2", nil)
+
+	err := p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed with. Duplicate flag use not detected", t.Name())
+		return
+	}
+
+	testArgs = []string{"progname", "--flag-arg2", "-ff"}
+
+	p = NewParser("", "description")
+	_ = p.Flag("f", "flag-arg1", nil)
+	_ = p.Flag("", "flag-arg2", nil)
+
+	err = p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed with. Duplicate flag use not detected", t.Name())
+		return
+	}
+
+	testArgs = []string{"progname", "--flag-arg2", "-f"}
+
+	p = NewParser("", "description")
+	_ = p.Flag("f", "flag-arg1", nil)
+	_ = p.Flag("", "flag-arg2", nil)
+
+	err = p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with. Fake duplicate flag detected", t.Name())
+		return
+	}
+}
+
+func TestDuplicatePolicy(t *testing.T) {
+	testArgs := []string{"progname", "cmd", "--level", "info", "--level", "debug", "-n", "1", "-n", "2", "-s", "a", "-s", "b", "-c", "x"}
+
+	p := NewParser("", "description")
+	p.OnDuplicate(DuplicateLastWins)
+	level := p.Selector("l", "level", []string{"info", "debug"}, nil)
+	number := p.Int("n", "number", &Options{Duplicate: DuplicateFirstWins})
+	scale := p.String("s", "scale", nil)
+	cmd := p.NewCommand("cmd", "")
+	cmd.OnDuplicate(DuplicateError)
+	_ = cmd.String("c", "config", nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	switch {
+	case *level != "debug":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "debug", *level)
+	case *number != 1:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 1, *number)
+	case *scale != "b":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "b", *scale)
+	}
+
+	p = NewParser("", "description")
+	p.OnDuplicate(DuplicateLastWins)
+	cmd = p.NewCommand("cmd", "")
+	cmd.OnDuplicate(DuplicateError)
+	_ = cmd.String("c", "config", nil)
+
+	err = p.Parse([]string{"progname", "cmd", "-c", "x", "-c", "y"})
+	if err == nil || err.Error() != "[-c|--config] can only be present once" {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), "[-c|--config] can only be present once", err)
+	}
+}
+
+func TestDuplicatePolicyFirstWinsFail(t *testing.T) {
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--level", "1", "--level", "x"}, errStr: "[-l|--level] bad integer value [x]"},
+		{args: []string{"progname", "--level", "1", "--level", "13"}, errStr: "[-l|--level] unlucky number"},
+		{args: []string{"progname", "--mode", "fast", "--mode", "slow"}, errStr: "bad value for [-m|--mode]. Allowed values are [fast safe]"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.OnDuplicate(DuplicateFirstWins)
+		p.Int("l", "level", &Options{Validate: func(args []string) error {
+			if args[0] == "13" {
+				return errors.New("unlucky number")
+			}
+			return nil
+		}})
+		p.Selector("m", "mode", []string{"fast", "safe"}, nil)
+
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}
+
+func TestDuplicatePolicyFile(t *testing.T) {
+	fpath1, fpath2 := "./test1.tmp", "./test2.tmp"
+	for _, fpath := range []string{fpath1, fpath2} {
+		f, err := os.Create(fpath)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.Close()
+		defer os.Remove(fpath)
+	}
+
+	testArgs := []string{"progname", "-f", fpath1, "-f", fpath2}
+
+	p := NewParser("", "")
+	file := p.File("f", "file", os.O_RDWR, 0666, &Options{Duplicate: DuplicateLastWins})
+
+	var replaced os.File
+	validate := func(args []string) error {
+		replaced = *file
+		return nil
+	}
+	p.GetArgs()[1].GetOpts().Validate = validate
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	defer file.Close()
+
+	if file.Name() != fpath2 {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), fpath2, file.Name())
+	}
+	if replaced.Name() != fpath1 {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), fpath1, replaced.Name())
+	}
+	if err := replaced.Close(); err == nil {
+		t.Errorf("Test %s failed with replaced file not being closed", t.Name())
+	}
+}
+
+func TestDuplicatePolicyFileFirstWins(t *testing.T) {
+	fpath1, fpath2 := "./test1.tmp", "./test2.tmp"
+	for _, fpath := range []string{fpath1, fpath2} {
+		f, err := os.Create(fpath)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.Close()
+		defer os.Remove(fpath)
+	}
+
+	testArgs := []string{"progname", "-f", fpath1, "-f", fpath2}
+
+	p := NewParser("", "")
+	file := p.File("f", "file", os.O_RDWR, 0666, &Options{Duplicate: DuplicateFirstWins})
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	defer file.Close()
+
+	if file.Name() != fpath1 {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), fpath1, file.Name())
+	}
+	if _, err := file.Stat(); err != nil {
+		t.Errorf("Test %s failed with first file being closed: %s", t.Name(), err.Error())
+	}
+
+	p = NewParser("", "")
+	file = p.File("f", "file", os.O_RDWR, 0666, &Options{Duplicate: DuplicateFirstWins})
+	err = p.Parse([]string{"progname", "-f", fpath1, "-f", "./missing/test3.tmp"})
+	if err == nil {
+		t.Errorf("Test %s failed with missing file not detected", t.Name())
+	}
+	file.Close()
+}
+
+func TestFailCaseSensitive(t *testing.T) {
+	testArgs := []string{"progname", "-F"}
+
+	p := NewParser("", "description")
+	_ = p.Flag("f", "flag", &Options{Required: true})
+
+	err := p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed with. Sees -F as -f", t.Name())
+		return
+	}
+}
+
+func TestFailExcessiveArguments(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "whatever"}
+
+	p := NewParser("", "description")
+	_ = p.Flag("f", "flag-arg1", nil)
+	_ = p.Flag("", "flag-arg2", nil)
+
+	err := p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed with. Excessive argument not detected", t.Name())
+		return
+	}
+}
+
+func TestStringAddArgumentFail(t *testing.T) {
+	type testCase struct {
+		testName, shortArg, longArg, failureMessage string
+	}
+	tt := []testCase{
+		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add String: short name must not exceed 1 character"},
+		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add String: long name should be provided"},
+		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add String: long name flag1 occurs more than once"},
+		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add String: short name F occurs more than once"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					rezString := fmt.Sprintf("%v", r)
+					if strings.Contains(rezString, tc.failureMessage) == false {
+						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
+					}
+				} else {
+					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
+				}
+			}()
+			p := NewParser("", "description")
+			_ = p.String("F", "flag1", nil)
+			_ = p.String(tc.shortArg, tc.longArg, nil)
+		})
+	}
+}
+
+func TestStringSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "test"}
+
+	p := NewParser("", "description")
+	s1 := p.String("f", "flag-arg1", nil)
+	s2 := p.String("", "flag-arg2", nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	if s1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if s2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+		return
+	}
+
+	if *s1 != "test" {
+		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "test", *s1)
+		return
+	}
+
+	if *s2 != "" {
+		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "\"\"", *s1)
+		return
+	}
+
+	if args := p.GetArgs(); args == nil {
+		t.Errorf("Test %s failed with args empty", t.Name())
+	} else if len(args) != 3 { // our two + help
+		t.Errorf("Test %s failed with wrong len", t.Name())
+	} else {
+		got := 0
+		for _, arg := range args {
+			switch arg.GetLname() {
+			case "flag-arg1":
+				if *arg.GetResult().(*string) != *s1 {
+					t.Errorf("Test %s failed with wrong arg value", t.Name())
+				}
+				got += 3
+			case "flag-arg2":
+				if *arg.GetResult().(*string) != "" {
+					t.Errorf("Test %s failed with non-nil result", t.Name())
+				}
+				got += 5
+			case "help":
+				got += 11
+			}
+		}
+		if got != 19 {
+			t.Errorf("Test %s failed with wrong args found", t.Name())
+		}
+	}
+}
+
+func TestStringSimple2(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "test"}
+
+	p := NewParser("", "description")
+	s1 := p.String("f", "flag-arg1", nil)
+	s2 := p.String("", "flag-arg2", nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	if s1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if s2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+		return
+	}
+
+	if *s1 != "test" {
+		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "test", *s1)
+		return
+	}
+
+	if *s2 != "" {
+		t.Errorf("Test %s failed. Want: [%s], got: [%s]", t.Name(), "\"\"", *s1)
+		return
+	}
+}
+
+func TestIntAddArgumentFail(t *testing.T) {
+	type testCase struct {
+		testName, shortArg, longArg, failureMessage string
+	}
+	tt := []testCase{
+		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add Int: short name must not exceed 1 character"},
+		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add Int: long name should be provided"},
+		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add Int: long name flag1 occurs more than once"},
+		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add Int: short name F occurs more than once"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					rezString := fmt.Sprintf("%v", r)
+					if strings.Contains(rezString, tc.failureMessage) == false {
+						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
+					}
+				} else {
+					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
+				}
+			}()
+			p := NewParser("", "description")
+			_ = p.Int("F", "flag1", nil)
+			_ = p.Int(tc.shortArg, tc.longArg, nil)
+		})
+	}
+}
+
+func TestIntSimple1(t *testing.T) {
+	val := 5150
+	testArgs := []string{"progname", "--flag-arg1", strconv.Itoa(val)}
+
+	p := NewParser("", "description")
+	i1 := p.Int("f", "flag-arg1", nil)
+	i2 := p.Int("", "flag-arg2", nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	if i1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if i2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+		return
+	}
+
+	if *i1 != val {
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), val, *i1)
+		return
+	}
+
+	if *i2 != 0 {
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 0, *i1)
+		return
+	}
+}
+
+func TestIntSimple2(t *testing.T) {
+	val := 5150
+	testArgs := []string{"progname", "--flag-arg1", strconv.Itoa(val)}
+
+	p := NewParser("", "description")
+	i1 := p.Int("f", "flag-arg1", nil)
+	i2 := p.Int("", "flag-arg2", nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	if i1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if i2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+		return
+	}
+
+	if *i1 != val {
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), val, *i1)
+		return
+	}
+
+	if *i2 != 0 {
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 0, *i1)
+		return
+	}
+}
+
+func TestIntFailSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1", "string"}
+
+	p := NewParser("", "description")
+	i1 := p.Int("f", "flag-arg1", nil)
+
+	err := p.Parse(testArgs)
+	errStr := "[-f|--flag-arg1] bad integer value [string]"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+		return
+	}
+
+	if i1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if *i1 != 0 {
+		t.Errorf("Test %s failed. Want: [0], got: [%d]", t.Name(), *i1)
+		return
+	}
+}
+
+func TestEqualIntFailSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1=string"}
+
+	p := NewParser("", "description")
+	i1 := p.Int("f", "flag-arg1", nil)
+
+	err := p.Parse(testArgs)
+	errStr := "[-f|--flag-arg1] bad integer value [string]"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+		return
+	}
+
+	if i1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if *i1 != 0 {
+		t.Errorf("Test %s failed. Want: [0], got: [%d]", t.Name(), *i1)
+		return
+	}
+}
+
+func TestEqualNoValFailSimple(t *testing.T) {
+	testArgs := []string{"progname", "--flag-arg1="}
+
+	p := NewParser("", "description")
+	i1 := p.Int("f", "flag-arg1", nil)
+
+	err := p.Parse(testArgs)
+	errStr := "not enough arguments for -f|--flag-arg1"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+		return
+	}
+
+	if i1 == nil {
+		t.Errorf("Test %s failed with flag1 being nil pointer", t.Name())
+		return
+	}
+
+	if *i1 != 0 {
+		t.Errorf("Test %s failed. Want: [0], got: [%d]", t.Name(), *i1)
+		return
+	}
+}
+
+func TestFileAddArgumentFail(t *testing.T) {
+	type testCase struct {
+		testName, shortArg, longArg, failureMessage string
+	}
+	tt := []testCase{
+		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add File: short name must not exceed 1 character"},
+		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add File: long name should be provided"},
+		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add File: long name flag1 occurs more than once"},
+		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add File: short name F occurs more than once"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					rezString := fmt.Sprintf("%v", r)
+					if strings.Contains(rezString, tc.failureMessage) == false {
+						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
+					}
+				} else {
+					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
+				}
+			}()
+			p := NewParser("", "description")
+			_ = p.File("F", "flag1", os.O_RDWR, 0666, nil)
+			_ = p.File(tc.shortArg, tc.longArg, os.O_RDWR, 0666, nil)
+		})
+	}
+}
+
+func TestFileSimple1(t *testing.T) {
+	// Test file location
+	fpath := "./test.tmp"
+	// Create test file
+	f, err := os.Create(fpath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	f.Close()
+	defer os.Remove(fpath)
+
+	testArgs := []string{"progname", "-f", fpath}
+
+	p := NewParser("", "")
+
+	file1 := p.File("f", "file", os.O_RDWR, 0666, &Options{Default: "./non-existent-file.tmp"})
+
+	err = p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	if file1 == nil {
+		t.Errorf("Test %s failed with file1 being nil pointer", t.Name())
+		return
+	}
+
+	defer file1.Close()
+
+	testString := "Test"
+	recSlice := make([]byte, 4)
+	_, err = file1.WriteString(testString)
+	if err != nil {
+		t.Errorf("Test %s write operation failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	file1.Seek(0, 0)
+	n, err := file1.Read(recSlice)
+	if err != nil {
+		t.Errorf("Test %s read operation failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	if n != 4 || string(recSlice) != testString {
+		t.Errorf("Test %s failed on read operation", t.Name())
+		return
+	}
+}
+
+func TestFileSimpleFail1(t *testing.T) {
+	// Not existing test file location
+	fpath := "./non-existent-file.tmp"
+	// To be shure there is no fake file
+	if _, err := os.Stat(fpath); os.IsNotExist(err) != true {
+		//we could remove it, but what if it's important
+		t.Errorf("Test %s failed. There is \"%s\" file in module directory, which must not exists for test purposes", t.Name(), fpath)
+		return
+	}
+
+	testArgs := []string{"progname"}
+
+	p := NewParser("", "")
+
+	_ = p.File("f", "file", os.O_RDWR, 0666, &Options{Default: fpath})
+
+	err := p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed. Parsing should fail.", t.Name())
+	}
+	err, ok := err.(*os.PathError)
+
+	if ok == false {
+		t.Errorf("Test %s failed with error: %s, that is not of *os.PathError type", t.Name(), err.Error())
+	}
+}
+
+func TestFileSimpleFail2(t *testing.T) {
+	// Not existing test file location
+	fpath := "./non-existent-file.tmp"
+	// To be shure there is no fake file
+	if _, err := os.Stat(fpath); os.IsNotExist(err) != true {
+		//we could remove it, but what if it's important
+		t.Errorf("Test %s failed. There is \"%s\" file in module directory, which must not exists for test purposes", t.Name(), fpath)
+		return
+	}
+
+	testArgs := []string{"progname", "-f", fpath}
+
+	p := NewParser("", "")
+
+	_ = p.File("f", "file", os.O_RDWR, 0666, nil)
+
+	err := p.Parse(testArgs)
+	if err == nil {
+		t.Errorf("Test %s failed. Parsing should fail.", t.Name())
+		return
+	}
+	err, ok := err.(*os.PathError)
+
+	if ok == false {
 		t.Errorf("Test %s failed with error: %s, that is not of *os.PathError type", t.Name(), err.Error())
 	}
 }
@@ -1244,6 +2316,53 @@ func TestFileListSimple1(t *testing.T) {
 	}
 }
 
+func TestFileListSeparator(t *testing.T) {
+	fpaths := []string{"./test1.tmp", "./test2.tmp", "./test3.tmp"}
+	for _, fpath := range fpaths {
+		f, err := os.Create(fpath)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.Close()
+		defer os.Remove(fpath)
+	}
+
+	testArgs := []string{"progname", "--file", fpaths[0] + "," + fpaths[1], "-f", fpaths[2]}
+
+	p := NewParser("", "")
+	files := p.FileList("f", "file", os.O_RDONLY, 0666, &Options{Separator: ","})
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	names := make([]string, 0, len(*files))
+	for _, file := range *files {
+		names = append(names, file.Name())
+		file.Close()
+	}
+	if !reflect.DeepEqual(names, fpaths) {
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), fpaths, names)
+	}
+
+	p = NewParser("", "")
+	files = p.FileList("f", "file", os.O_RDONLY, 0666, &Options{Separator: ",", Default: fpaths[0] + "," + fpaths[1]})
+	if err := p.Parse([]string{"progname"}); err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	names = names[:0]
+	for _, file := range *files {
+		names = append(names, file.Name())
+		file.Close()
+	}
+	if !reflect.DeepEqual(names, fpaths[:2]) {
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), fpaths[:2], names)
+	}
+}
+
 func TestFloatListAddArgumentFail(t *testing.T) {
 	type testCase struct {
 		testName, shortArg, longArg, failureMessage string
@@ -1429,6 +2548,256 @@ func TestStringListSimple1(t *testing.T) {
 	}
 }
 
+func TestListNArgs(t *testing.T) {
+	testArgs := []string{"progname", "--files", "a", "b", "c", "-v", "--ints", "1", "-2", "3", "--floats", "1.5", "--", "pos"}
+
+	p := NewParser("", "description")
+	files := p.StringList("f", "files", &Options{NArgs: "+"})
+	verbose := p.Flag("v", "verbose", nil)
+	ints := p.IntList("i", "ints", &Options{NArgs: "2"})
+	floats := p.FloatList("", "floats", &Options{NArgs: "*"})
+
+	err := p.Parse(testArgs)
+	if err == nil || err.Error() != "unknown arguments 3 pos" {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), "unknown arguments 3 pos", err)
+		return
+	}
+
+	switch {
+	case !reflect.DeepEqual(*files, []string{"a", "b", "c"}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []string{"a", "b", "c"}, *files)
+	case !*verbose:
+		t.Errorf("Test %s failed with verbose being false", t.Name())
+	case !reflect.DeepEqual(*ints, []int{1, -2}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []int{1, -2}, *ints)
+	case !reflect.DeepEqual(*floats, []float64{1.5}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []float64{1.5}, *floats)
+	}
+}
+
+func TestListNArgsTerminator(t *testing.T) {
+	testArgs := []string{"progname", "--files", "a", "b", "--", "pos"}
+
+	p := NewParser("", "description")
+	files := p.StringList("f", "files", &Options{NArgs: "+"})
+	pos := p.StringPositional(nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	switch {
+	case !reflect.DeepEqual(*files, []string{"a", "b"}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []string{"a", "b"}, *files)
+	case *pos != "pos":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "pos", *pos)
+	}
+}
+
+func TestListNArgsRange(t *testing.T) {
+	testArgs := []string{"progname", "--names", "a", "--names", "b", "c", "d", "--empty", "--names=e"}
+
+	p := NewParser("", "description")
+	names := p.StringList("n", "names", &Options{NArgs: "1-2"})
+	empty := p.StringList("e", "empty", &Options{NArgs: "*"})
+	pos := p.StringPositional(nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	switch {
+	case !reflect.DeepEqual(*names, []string{"a", "b", "c", "e"}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []string{"a", "b", "c", "e"}, *names)
+	case len(*empty) != 0:
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []string{}, *empty)
+	case *pos != "d":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "d", *pos)
+	}
+}
+
+func TestListNArgsFail(t *testing.T) {
+	type testCase struct {
+		testName, nargs, failureMessage string
+		args                            []string
+	}
+	tt := []testCase{
+		{testName: "Missing value", nargs: "+", args: []string{"progname", "--names", "-v"}, failureMessage: "[-n|--names] must be followed by a string"},
+		{testName: "Not enough values", nargs: "3", args: []string{"progname", "--names", "a", "b"}, failureMessage: "[-n|--names] must be followed by at least 3 values"},
+		{testName: "Not enough values with equals char", nargs: "2+", args: []string{"progname", "--names=a", "b"}, failureMessage: "[-n|--names] must be followed by at least 2 values"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			p := NewParser("", "description")
+			_ = p.StringList("n", "names", &Options{NArgs: tc.nargs})
+			_ = p.Flag("v", "verbose", nil)
+
+			err := p.Parse(tc.args)
+			if err == nil || err.Error() != tc.failureMessage {
+				t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.failureMessage, err)
+			}
+		})
+	}
+}
+
+func TestListNArgsAddArgumentFail(t *testing.T) {
+	type testCase struct {
+		testName, nargs, failureMessage string
+	}
+	tt := []testCase{
+		{testName: "Bad value", nargs: "many", failureMessage: "unable to add StringList: bad NArgs value [many]"},
+		{testName: "Bad range", nargs: "3-2", failureMessage: "unable to add StringList: bad NArgs value [3-2]"},
+		{testName: "Zero values", nargs: "0", failureMessage: "unable to add StringList: bad NArgs value [0]"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || fmt.Sprintf("%v", r) != tc.failureMessage {
+					t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
+				}
+			}()
+			p := NewParser("", "description")
+			_ = p.StringList("n", "names", &Options{NArgs: tc.nargs})
+		})
+	}
+
+	defer func() {
+		failureMessage := "unable to add String: NArgs is only supported by list arguments"
+		if r := recover(); r == nil || fmt.Sprintf("%v", r) != failureMessage {
+			t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, failureMessage)
+		}
+	}()
+	p := NewParser("", "description")
+	_ = p.String("n", "name", &Options{NArgs: "+"})
+}
+
+func TestListNArgsUsage(t *testing.T) {
+	p := NewParser("progname", "description")
+	_ = p.StringList("f", "files", &Options{NArgs: "+", Required: true, Metavar: "FILE"})
+	_ = p.IntList("", "point-xy", &Options{NArgs: "2"})
+	_ = p.FloatList("", "floats", &Options{NArgs: "*"})
+
+	expected := "usage: progname [-h|--help] -f|--files FILE [FILE ...] [--point-xy POINT_XY\n                POINT_XY] [--floats [FLOATS ...]]"
+	usage := p.Usage(nil)
+	if !strings.HasPrefix(usage, expected) {
+		t.Errorf("Test %s failed: expected usage to start with:\n%s\ngot:\n%s", t.Name(), expected, usage)
+	}
+}
+
+func TestListSeparator(t *testing.T) {
+	testArgs := []string{"progname", "--tags", "a,b\\,c", "--tags", "'d,e',\"f\"", "--ports", "80,443", "--ports=8080", "-w", "0.5::1.5"}
+
+	p := NewParser("", "description")
+	tags := p.StringList("t", "tags", &Options{Separator: ","})
+	ports := p.IntList("p", "ports", &Options{Separator: ","})
+	weights := p.FloatList("w", "weights", &Options{Separator: "::"})
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	switch {
+	case !reflect.DeepEqual(*tags, []string{"a", "b,c", "d,e", "f"}):
+		t.Errorf("Test %s failed. Want: %q, got: %q", t.Name(), []string{"a", "b,c", "d,e", "f"}, *tags)
+	case !reflect.DeepEqual(*ports, []int{80, 443, 8080}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []int{80, 443, 8080}, *ports)
+	case !reflect.DeepEqual(*weights, []float64{0.5, 1.5}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []float64{0.5, 1.5}, *weights)
+	}
+}
+
+func TestListSeparatorFail(t *testing.T) {
+	type testCase struct {
+		testName, failureMessage string
+		args                     []string
+	}
+	tt := []testCase{
+		{testName: "Bad integer", args: []string{"progname", "--ports", "80,http"}, failureMessage: "[-p|--ports] bad integer value [http]"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			p := NewParser("", "description")
+			_ = p.StringList("t", "tags", &Options{Separator: ","})
+			_ = p.IntList("p", "ports", &Options{Separator: ","})
+
+			err := p.Parse(tc.args)
+			if err == nil || err.Error() != tc.failureMessage {
+				t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.failureMessage, err)
+			}
+		})
+	}
+}
+func TestListSeparatorLiteral(t *testing.T) {
+	type testCase struct {
+		value  string
+		result []string
+	}
+	testCases := []testCase{
+		{value: `C:\dir,O'Brien`, result: []string{`C:\dir`, "O'Brien"}},
+		{value: `a\,b,c\d,e\`, result: []string{"a,b", `c\d`, `e\`}},
+		{value: `'a,b',"c,d",'e`, result: []string{"a,b", "c,d", "'e"}},
+		{value: `'a'b,c`, result: []string{"'a'b", "c"}},
+		{value: `"it's",'',x`, result: []string{"it's", "", "x"}},
+		{value: `a,'b',`, result: []string{"a", "b", ""}},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		tags := p.StringList("t", "tags", &Options{Separator: ","})
+
+		if err := p.Parse([]string{"progname", "--tags", tc.value}); err != nil {
+			t.Errorf("Test %s failed for [%s] with error: %s", t.Name(), tc.value, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(*tags, tc.result) {
+			t.Errorf("Test %s failed for [%s]. Want: %q, got: %q", t.Name(), tc.value, tc.result, *tags)
+		}
+	}
+
+	p := NewParser("", "description")
+	exprs := p.RegexpList("e", "exclude", &Options{Separator: ","})
+	if err := p.Parse([]string{"progname", "--exclude", `\.go$,^vendor/`}); err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	if len(*exprs) != 2 || (*exprs)[0].String() != `\.go$` || (*exprs)[1].String() != `^vendor/` {
+		t.Errorf("Test %s failed. Want: [\\.go$ ^vendor/], got: %v", t.Name(), *exprs)
+	}
+}
+
+func TestListSeparatorDefault(t *testing.T) {
+	p := NewParser("", "description")
+	tags := p.StringList("t", "tags", &Options{Separator: ",", Default: "a,b"})
+	ports := p.IntList("p", "ports", &Options{Separator: ",", Default: "80,443"})
+	weights := p.FloatList("w", "weights", &Options{Separator: ",", Default: []float64{1.5}})
+	pairs := p.StringList("", "pair", &Options{NArgs: "2", Separator: ",", Default: "a,b"})
+
+	err := p.Parse([]string{"progname"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	switch {
+	case !reflect.DeepEqual(*tags, []string{"a", "b"}):
+		t.Errorf("Test %s failed. Want: %q, got: %q", t.Name(), []string{"a", "b"}, *tags)
+	case !reflect.DeepEqual(*ports, []int{80, 443}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []int{80, 443}, *ports)
+	case !reflect.DeepEqual(*weights, []float64{1.5}):
+		t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), []float64{1.5}, *weights)
+	case !reflect.DeepEqual(*pairs, []string{"a", "b"}):
+		t.Errorf("Test %s failed. Want: %q, got: %q", t.Name(), []string{"a", "b"}, *pairs)
+	case p.GetArgs()[1].GetParsed():
+		t.Errorf("Test %s failed with default value being parsed", t.Name())
+	}
+}
+
 func TestListAddArgumentFail(t *testing.T) {
 	type testCase struct {
 		testName, shortArg, longArg, failureMessage string
@@ -1483,14 +2852,95 @@ func TestListSimple1(t *testing.T) {
 		return
 	}
 
-	if !reflect.DeepEqual(*l1, list1Expect) {
-		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), list1Expect, *l1)
-		return
-	}
+	if !reflect.DeepEqual(*l1, list1Expect) {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), list1Expect, *l1)
+		return
+	}
+
+	if !reflect.DeepEqual(*l2, list2Expect) {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), list2Expect, *l2)
+		return
+	}
+}
+
+func TestOptionalValue(t *testing.T) {
+	testArgs := []string{"progname", "--color", "never", "--level=3", "-j", "-sfast"}
+
+	p := NewParser("", "description")
+	color := p.Selector("c", "color", []string{"always", "never", "auto"}, &Options{ConstValue: "always", Default: "auto"})
+	level := p.Int("l", "level", &Options{ConstValue: 1})
+	jobs := p.Int("j", "jobs", &Options{ConstValue: 4})
+	speed := p.String("s", "speed", &Options{ConstValue: "normal"})
+	pos := p.StringPositional(nil)
+
+	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	switch {
+	case *color != "always":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "always", *color)
+	case *level != 3:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 3, *level)
+	case *jobs != 4:
+		t.Errorf("Test %s failed. Want: %d, got: %d", t.Name(), 4, *jobs)
+	case *speed != "fast":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "fast", *speed)
+	case *pos != "never":
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "never", *pos)
+	}
+}
+
+func TestOptionalValueDefault(t *testing.T) {
+	p := NewParser("", "description")
+	color := p.Selector("c", "color", []string{"always", "never", "auto"}, &Options{ConstValue: "always", Default: "auto"})
+
+	err := p.Parse([]string{"progname"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	if *color != "auto" {
+		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), "auto", *color)
+	}
+}
+
+func TestOptionalValueAddArgumentFail(t *testing.T) {
+	type testCase struct {
+		testName, failureMessage string
+		add                      func(p *Parser)
+	}
+	tt := []testCase{
+		{testName: "Wrong type", failureMessage: "unable to add Int: cannot use ConstValue type [string] as value of pointer with type [*int]",
+			add: func(p *Parser) { p.Int("l", "level", &Options{ConstValue: "1"}) }},
+		{testName: "Not allowed value", failureMessage: "unable to add Selector: ConstValue [red] is not one of allowed values [always never]",
+			add: func(p *Parser) { p.Selector("c", "color", []string{"always", "never"}, &Options{ConstValue: "red"}) }},
+		{testName: "Unsupported type", failureMessage: "unable to add StringList: ConstValue is only supported by String, Int, Float, Duration and Selector arguments",
+			add: func(p *Parser) { p.StringList("s", "strings", &Options{ConstValue: []string{"a"}}) }},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || fmt.Sprintf("%v", r) != tc.failureMessage {
+					t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
+				}
+			}()
+			tc.add(NewParser("", "description"))
+		})
+	}
+}
+
+func TestOptionalValueUsage(t *testing.T) {
+	p := NewParser("progname", "description")
+	_ = p.String("c", "color", &Options{ConstValue: "always", Metavar: "WHEN"})
+	_ = p.Int("l", "level", &Options{ConstValue: 1})
 
-	if !reflect.DeepEqual(*l2, list2Expect) {
-		t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), list2Expect, *l2)
-		return
+	expected := "usage: progname [-h|--help] [-c|--color[=WHEN]] [-l|--level[=LEVEL]]"
+	usage := p.Usage(nil)
+	if !strings.HasPrefix(usage, expected) {
+		t.Errorf("Test %s failed: expected usage to start with:\n%s\ngot:\n%s", t.Name(), expected, usage)
 	}
 }
 
@@ -1819,6 +3269,146 @@ func TestOptsValidatePass2(t *testing.T) {
 	}
 }
 
+func TestOptsCheck(t *testing.T) {
+	positive := func(value interface{}) error {
+		if value.(int) <= 0 {
+			return fmt.Errorf("value [%d] must be positive", value)
+		}
+		return nil
+	}
+
+	type testCase struct {
+		args   []string
+		opts   *Options
+		want   int
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--count", "5"}, opts: &Options{Check: positive}, want: 5},
+		{args: []string{"progname", "--count", "-5"}, opts: &Options{Check: positive}, errStr: "[-c|--count] value [-5] must be positive"},
+		{args: []string{"progname"}, opts: &Options{Check: positive, Default: 3}, want: 3},
+		{args: []string{"progname"}, opts: &Options{Check: positive, Default: 0}, errStr: "[-c|--count] value [0] must be positive"},
+		{args: []string{"progname"}, opts: &Options{Check: positive}, want: 0},
+		{args: []string{"progname", "--count"}, opts: &Options{Check: positive, ConstValue: -1}, errStr: "[-c|--count] value [-1] must be positive"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("progname", "")
+		count := p.Int("c", "count", tc.opts)
+
+		err := p.Parse(tc.args)
+		if tc.errStr != "" {
+			if err == nil || err.Error() != tc.errStr {
+				t.Errorf("Test %s failed for %q. Expected error [%s], got [%+v]", t.Name(), tc.args, tc.errStr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %s failed for %q with error: %s", t.Name(), tc.args, err.Error())
+			continue
+		}
+		if *count != tc.want {
+			t.Errorf("Test %s failed for %q. Want: [%d], got: [%d]", t.Name(), tc.args, tc.want, *count)
+		}
+	}
+}
+
+func TestOptsCheckTypes(t *testing.T) {
+	var checked []interface{}
+	record := func(value interface{}) error {
+		checked = append(checked, value)
+		return nil
+	}
+
+	p := NewParser("progname", "")
+	p.StringList("s", "str", &Options{Check: record})
+	p.Duration("d", "duration", &Options{Check: record, Default: time.Minute})
+	p.IP("", "ip", &Options{Check: record, Default: "127.0.0.1"})
+	p.FloatPositional(&Options{Check: record})
+
+	err := p.Parse([]string{"progname", "-s", "a", "--str", "b", "1.5"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+
+	want := []interface{}{[]string{"a", "b"}, time.Minute, net.IPv4(127, 0, 0, 1), 1.5}
+	if !reflect.DeepEqual(checked, want) {
+		t.Errorf("Test %s failed. Want: %#v, got: %#v", t.Name(), want, checked)
+	}
+}
+
+func TestOptsCheckPositionalDefault(t *testing.T) {
+	p := NewParser("progname", "")
+	p.StringPositional(&Options{
+		Default: "",
+		Check: func(value interface{}) error {
+			if value.(string) == "" {
+				return errors.New("value must not be empty")
+			}
+			return nil
+		},
+	})
+
+	err := p.Parse([]string{"progname"})
+	errStr := "[_positionalArg_progname_1] value must not be empty"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	}
+}
+
+// labelsFlag is flag.Value, which is not a pointer
+type labelsFlag map[string]string
+
+func (l labelsFlag) String() string {
+	return fmt.Sprint(map[string]string(l))
+}
+
+func (l labelsFlag) Set(value string) error {
+	kv := strings.SplitN(value, "=", 2)
+	if len(kv) != 2 {
+		return errors.New("expected key=value")
+	}
+	l[kv[0]] = kv[1]
+	return nil
+}
+
+func TestOptsCheckFlagSet(t *testing.T) {
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--label", "env=prod"}},
+		{args: []string{"progname"}, errStr: "[--label] at least one label is required"},
+	}
+
+	for _, tc := range testCases {
+		fs := flag.NewFlagSet("lib", flag.ContinueOnError)
+		fs.Var(labelsFlag{}, "label", "Labels")
+
+		p := NewParser("progname", "description")
+		p.AddFlagSet(fs)
+		for _, a := range p.GetArgs() {
+			if a.GetLname() == "label" {
+				a.GetOpts().Check = func(value interface{}) error {
+					if len(value.(labelsFlag)) == 0 {
+						return errors.New("at least one label is required")
+					}
+					return nil
+				}
+			}
+		}
+
+		err := p.Parse(tc.args)
+		if tc.errStr == "" && err != nil {
+			t.Errorf("Test %s failed for %q with error: %s", t.Name(), tc.args, err.Error())
+		} else if tc.errStr != "" && (err == nil || err.Error() != tc.errStr) {
+			t.Errorf("Test %s failed for %q. Expected error [%s], got [%+v]", t.Name(), tc.args, tc.errStr, err)
+		}
+	}
+}
+
 var pUsage = `usage: verylongprogname <Command> [-h|--help] [-s|--verylongstring-flag1
                         "<value>"] [-i|--integer-flag1 <integer>]
 
@@ -1885,128 +3475,379 @@ func TestUsageSimple1(t *testing.T) {
 	if cmd1Usage != cmd1.Usage(nil) {
 		t.Errorf("cmd1Usage: get:\n%s\nexpect:\n%s", cmd1.Usage(nil), cmd1Usage)
 	}
-	if cmd2Usage != cmd2.Usage(nil) {
-		t.Errorf("cmd2Usage: get:\n%s\nexpect:\n%s", cmd2.Usage(nil), cmd2Usage)
+	if cmd2Usage != cmd2.Usage(nil) {
+		t.Errorf("cmd2Usage: get:\n%s\nexpect:\n%s", cmd2.Usage(nil), cmd2Usage)
+	}
+}
+
+func TestUsageHidden1(t *testing.T) {
+	p := NewParser("verylongprogname", "prog description")
+
+	cmd1 := p.NewCommand("veryverylongcmd1", "cmd1 description")
+	_ = cmd1.Flag("f", "verylongflag1", &Options{Help: "flag1 description"})
+	_ = cmd1.Flag("a", "verylongflagA", &Options{Required: true, Help: "flag1 description"})
+	_ = p.String("s", "verylongstring-flag1", &Options{Help: "string1 description"})
+	_ = p.Int("i", "integer-flag1", &Options{Help: "integer1 description"})
+	_ = p.Int("I", "integer-flag2", &Options{Help: DisableDescription})
+
+	_ = p.NewCommand("cmd2", "cmd2 description")
+
+	cmd3 := p.NewCommand("cmd3", DisableDescription)
+	_ = cmd3.Flag("f", "verylongflag1", &Options{Help: "flag1 description"})
+	_ = cmd3.Flag("a", "verylongflagA", &Options{Required: true, Help: "flag1 description"})
+
+	p.Parse(os.Args)
+
+	if pUsage != p.Usage(nil) {
+		t.Errorf("%s", p.Usage(nil))
+	}
+	if cmd1Usage != cmd1.Usage(nil) {
+		t.Errorf("%s", cmd1.Usage(nil))
+	}
+}
+
+func TestUsageSubCommand(t *testing.T) {
+	expected := `[sub]Command required
+usage: zooprog <Command> [-h|--help]
+
+               Program that walks us through the zoo
+
+Commands:
+
+  dog  We are going to see dog
+
+Arguments:
+
+  -h  --help  Print help information
+
+`
+
+	parser := NewParser("zooprog", "Program that walks us through the zoo")
+
+	// dog command
+	parser.
+		NewCommand("dog", "We are going to see dog"). // adds command to parser
+		NewCommand("speak", "Make the dog speak")     // adds subcommand to previous command
+
+	err := newSubCommandError(&parser.Command)
+	actual := parser.Usage(err)
+	if expected != actual {
+		t.Errorf("Expectations unmet. expected: %s, actual: %s", expected, actual)
+	}
+}
+
+func TestStringMissingArgFail(t *testing.T) {
+	testArgs := []string{"progname", "-s"}
+
+	p := NewParser("progname", "Prog description")
+
+	_ = p.String("s", "string", &Options{Required: true, Help: "A test string"})
+
+	err := p.Parse(testArgs)
+
+	if err != nil {
+		// Test should pass on failure
+		if err.Error() != "not enough arguments for -s|--string" {
+			t.Errorf("Test %s failed: expected error [%s], got error [%s]", t.Name(), "not enough arguments for -s|--string", err.Error())
+		}
+	}
+}
+
+func TestIntMissingArgFail(t *testing.T) {
+	testArgs := []string{"progname", "-i"}
+
+	p := NewParser("progname", "Prog description")
+
+	_ = p.Int("i", "integer", &Options{Required: true, Help: "A test integer"})
+
+	err := p.Parse(testArgs)
+
+	if err != nil {
+		// Test should pass on failure
+		errStr := "not enough arguments for -i|--integer"
+		if err.Error() != errStr {
+			t.Errorf("Test %s failed: expected error [%s], got error [%s]", t.Name(), errStr, err.Error())
+		}
+	}
+}
+
+func TestFlagDefaultValuePass(t *testing.T) {
+	testArgs := []string{"progname"}
+
+	p := NewParser("progname", "Prog description")
+
+	f := p.Flag("f", "flag", &Options{Default: false})
+
+	err := p.Parse(testArgs)
+
+	// Should fail on failure
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	// Should fail if not false
+	if *f != false {
+		t.Errorf("expected [false] but found [%t]", *f)
+	}
+}
+
+func TestFlagDefaultValueShouldIgnoreTrue(t *testing.T) {
+	testArgs := []string{"progname"}
+
+	p := NewParser("progname", "Prog description")
+
+	f := p.Flag("f", "flag", &Options{Default: true})
+
+	err := p.Parse(testArgs)
+
+	// Should fail on failure
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	// Should fail if not false
+	if *f != false {
+		t.Errorf("expected [false] but found [%t]", *f)
+	}
+}
+
+func TestStringDefaultValuePass(t *testing.T) {
+	testArgs := []string{"progname"}
+	testString := "test string"
+
+	p := NewParser("progname", "Prog description")
+
+	s := p.String("s", "string", &Options{Default: testString})
+
+	err := p.Parse(testArgs)
+
+	// Should fail on failure
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	// Should fail if not true
+	if *s != testString {
+		t.Errorf("expected [string], got [%T]", *s)
+	}
+}
+
+func TestStringDefaultValueFail(t *testing.T) {
+	testArgs := []string{"progname"}
+
+	p := NewParser("progname", "Prog description")
+
+	_ = p.String("s", "string", &Options{Default: true})
+
+	err := p.Parse(testArgs)
+
+	// Should pass on failure
+	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*string]" {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
+	}
+}
+
+func TestIntDefaultValuePass(t *testing.T) {
+	testArgs := []string{"progname"}
+	testVal := 5150
+
+	p := NewParser("progname", "Prog description")
+
+	i := p.Int("i", "integer", &Options{Default: testVal})
+
+	err := p.Parse(testArgs)
+
+	// Should fail on failure
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	// Should fail if not true
+	if *i != testVal {
+		t.Errorf("expected [%d], got [%d]", testVal, *i)
 	}
 }
 
-func TestUsageHidden1(t *testing.T) {
-	p := NewParser("verylongprogname", "prog description")
-
-	cmd1 := p.NewCommand("veryverylongcmd1", "cmd1 description")
-	_ = cmd1.Flag("f", "verylongflag1", &Options{Help: "flag1 description"})
-	_ = cmd1.Flag("a", "verylongflagA", &Options{Required: true, Help: "flag1 description"})
-	_ = p.String("s", "verylongstring-flag1", &Options{Help: "string1 description"})
-	_ = p.Int("i", "integer-flag1", &Options{Help: "integer1 description"})
-	_ = p.Int("I", "integer-flag2", &Options{Help: DisableDescription})
+func TestIntDefaultValueFail(t *testing.T) {
+	testArgs := []string{"progname"}
 
-	_ = p.NewCommand("cmd2", "cmd2 description")
+	p := NewParser("progname", "Prog description")
 
-	cmd3 := p.NewCommand("cmd3", DisableDescription)
-	_ = cmd3.Flag("f", "verylongflag1", &Options{Help: "flag1 description"})
-	_ = cmd3.Flag("a", "verylongflagA", &Options{Required: true, Help: "flag1 description"})
+	_ = p.Int("i", "integer", &Options{Default: "fail"})
 
-	p.Parse(os.Args)
+	err := p.Parse(testArgs)
 
-	if pUsage != p.Usage(nil) {
-		t.Errorf("%s", p.Usage(nil))
-	}
-	if cmd1Usage != cmd1.Usage(nil) {
-		t.Errorf("%s", cmd1.Usage(nil))
+	// Should pass on failure
+	if err == nil || err.Error() != "cannot use default type [string] as value of pointer with type [*int]" {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
 	}
 }
 
-func TestUsageSubCommand(t *testing.T) {
-	expected := `[sub]Command required
-usage: zooprog <Command> [-h|--help]
+func TestFileDefaultValuePass(t *testing.T) {
+	// Test file location
+	fpath := "./test.tmp"
+	// Create test file
+	f, err := os.Create(fpath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	f.Close()
+	defer os.Remove(fpath)
 
-               Program that walks us through the zoo
+	testArgs := []string{"progname"}
 
-Commands:
+	p := NewParser("", "")
 
-  dog  We are going to see dog
+	file1 := p.File("f", "file", os.O_RDWR, 0666, &Options{Default: fpath})
 
-Arguments:
+	err = p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	defer file1.Close()
+}
 
-  -h  --help  Print help information
+func TestFileDefaultValueFail(t *testing.T) {
+	// Test file location
+	fpath := "./test.tmp"
+	// Create test file
+	f, err := os.Create(fpath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	f.Close()
+	defer os.Remove(fpath)
 
-`
+	testArgs := []string{"progname"}
 
-	parser := NewParser("zooprog", "Program that walks us through the zoo")
+	p := NewParser("", "")
 
-	// dog command
-	parser.
-		NewCommand("dog", "We are going to see dog"). // adds command to parser
-		NewCommand("speak", "Make the dog speak")     // adds subcommand to previous command
+	file1 := p.File("f", "file", os.O_RDWR, 0666, &Options{Default: true})
 
-	err := newSubCommandError(&parser.Command)
-	actual := parser.Usage(err)
-	if expected != actual {
-		t.Errorf("Expectations unmet. expected: %s, actual: %s", expected, actual)
+	err = p.Parse(testArgs)
+	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*string]" {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
 	}
+	defer file1.Close()
 }
 
-func TestStringMissingArgFail(t *testing.T) {
-	testArgs := []string{"progname", "-s"}
+func TestFileListDefaultValuePass(t *testing.T) {
+	testArgs := []string{"progname"}
+	// Test files location
+	fpaths := []string{"./test1.tmp", "./test2.tmp"}
+	// Create test files
+	for _, fpath := range fpaths {
+		f, err := os.Create(fpath)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.Close()
+		defer os.Remove(fpath)
+	}
 
 	p := NewParser("progname", "Prog description")
 
-	_ = p.String("s", "string", &Options{Required: true, Help: "A test string"})
+	files := p.FileList("f", "float", os.O_RDWR, 0666, &Options{Default: fpaths})
 
 	err := p.Parse(testArgs)
 
 	if err != nil {
-		// Test should pass on failure
-		if err.Error() != "not enough arguments for -s|--string" {
-			t.Errorf("Test %s failed: expected error [%s], got error [%s]", t.Name(), "not enough arguments for -s|--string", err.Error())
+		t.Error(err.Error())
+	}
+	for i, file := range *files {
+		defer file.Close()
+		testString := "Test"
+		recSlice := make([]byte, 4)
+		_, err = file.WriteString(testString)
+		if err != nil {
+			t.Errorf("Test %s write operation with file: %s failed with error: %s", t.Name(), fpaths[i], err.Error())
+			return
+		}
+		file.Seek(0, 0)
+		n, err := file.Read(recSlice)
+		if err != nil {
+			t.Errorf("Test %s read operation with file: %s failed with error: %s", t.Name(), fpaths[i], err.Error())
+			return
+		}
+		if n != 4 || string(recSlice) != testString {
+			t.Errorf("Test %s failed with file: %s on read operation", t.Name(), fpaths[i])
+			return
 		}
 	}
+
 }
 
-func TestIntMissingArgFail(t *testing.T) {
-	testArgs := []string{"progname", "-i"}
+func TestFloatListDefaultValuePass(t *testing.T) {
+	testArgs := []string{"progname"}
+	testList := []float64{12.0, -10}
 
 	p := NewParser("progname", "Prog description")
 
-	_ = p.Int("i", "integer", &Options{Required: true, Help: "A test integer"})
+	s := p.FloatList("f", "float", &Options{Default: testList})
 
 	err := p.Parse(testArgs)
 
-	if err != nil {
-		// Test should pass on failure
-		errStr := "not enough arguments for -i|--integer"
-		if err.Error() != errStr {
-			t.Errorf("Test %s failed: expected error [%s], got error [%s]", t.Name(), errStr, err.Error())
-		}
+	switch {
+	// Should fail on failure
+	case err != nil:
+		t.Error(err.Error())
+	// Should fail if not true
+	case !reflect.DeepEqual(*s, testList):
+		t.Errorf("expected [%v], got [%v]", testList, *s)
 	}
 }
 
-func TestFlagDefaultValuePass(t *testing.T) {
+func TestIntListDefaultValuePass(t *testing.T) {
 	testArgs := []string{"progname"}
+	testList := []int{12, -10}
 
 	p := NewParser("progname", "Prog description")
 
-	f := p.Flag("f", "flag", &Options{Default: false})
+	s := p.IntList("i", "int", &Options{Default: testList})
 
 	err := p.Parse(testArgs)
 
+	switch {
 	// Should fail on failure
-	if err != nil {
+	case err != nil:
 		t.Error(err.Error())
+	// Should fail if not true
+	case !reflect.DeepEqual(*s, testList):
+		t.Errorf("expected [%v], got [%v]", testList, *s)
 	}
+}
 
-	// Should fail if not false
-	if *f != false {
-		t.Errorf("expected [false] but found [%t]", *f)
+func TestStringListDefaultValuePass(t *testing.T) {
+	testArgs := []string{"progname"}
+	testList := []string{"test", "list"}
+
+	p := NewParser("progname", "Prog description")
+
+	s := p.StringList("s", "string", &Options{Default: testList})
+
+	err := p.Parse(testArgs)
+
+	switch {
+	// Should fail on failure
+	case err != nil:
+		t.Error(err.Error())
+	// Should fail if not true
+	case !reflect.DeepEqual(*s, testList):
+		t.Errorf("expected [%v], got [%v]", testList, *s)
 	}
 }
 
-func TestFlagDefaultValueShouldIgnoreTrue(t *testing.T) {
+func TestListDefaultValuePass(t *testing.T) {
 	testArgs := []string{"progname"}
+	testList := []string{"test", "list"}
 
 	p := NewParser("progname", "Prog description")
 
-	f := p.Flag("f", "flag", &Options{Default: true})
+	s := p.List("s", "string", &Options{Default: testList})
 
 	err := p.Parse(testArgs)
 
@@ -2015,55 +3856,98 @@ func TestFlagDefaultValueShouldIgnoreTrue(t *testing.T) {
 		t.Error(err.Error())
 	}
 
-	// Should fail if not false
-	if *f != false {
-		t.Errorf("expected [false] but found [%t]", *f)
+	// Should fail if not true
+	if !reflect.DeepEqual(*s, testList) {
+		t.Errorf("expected [%v], got [%v]", testList, *s)
 	}
 }
 
-func TestStringDefaultValuePass(t *testing.T) {
+func TestFileListDefaultValueFail(t *testing.T) {
 	testArgs := []string{"progname"}
-	testString := "test string"
 
 	p := NewParser("progname", "Prog description")
 
-	s := p.String("s", "string", &Options{Default: testString})
+	_ = p.FileList("f", "float", os.O_RDWR, 0666, &Options{Default: false})
 
 	err := p.Parse(testArgs)
 
-	// Should fail on failure
-	if err != nil {
-		t.Error(err.Error())
+	// Should pass on failure
+	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]string]"
+	if err == nil || err.Error() != failureMessage {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
 	}
+}
 
-	// Should fail if not true
-	if *s != testString {
-		t.Errorf("expected [string], got [%T]", *s)
+func TestFloatListDefaultValueFail(t *testing.T) {
+	testArgs := []string{"progname"}
+
+	p := NewParser("progname", "Prog description")
+
+	_ = p.FloatList("f", "float", &Options{Default: false})
+
+	err := p.Parse(testArgs)
+
+	// Should pass on failure
+	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]float64]"
+	if err == nil || err.Error() != failureMessage {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
+	}
+}
+
+func TestIntListDefaultValueFail(t *testing.T) {
+	testArgs := []string{"progname"}
+
+	p := NewParser("progname", "Prog description")
+
+	_ = p.IntList("i", "int", &Options{Default: false})
+
+	err := p.Parse(testArgs)
+
+	// Should pass on failure
+	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]int]"
+	if err == nil || err.Error() != failureMessage {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
+	}
+}
+
+func TestStringListDefaultValueFail(t *testing.T) {
+	testArgs := []string{"progname"}
+
+	p := NewParser("progname", "Prog description")
+
+	_ = p.StringList("s", "string", &Options{Default: false})
+
+	err := p.Parse(testArgs)
+
+	// Should pass on failure
+	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]string]"
+	if err == nil || err.Error() != failureMessage {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
 	}
 }
 
-func TestStringDefaultValueFail(t *testing.T) {
+func TestListDefaultValueFail(t *testing.T) {
 	testArgs := []string{"progname"}
 
 	p := NewParser("progname", "Prog description")
 
-	_ = p.String("s", "string", &Options{Default: true})
+	_ = p.List("s", "string", &Options{Default: false})
 
 	err := p.Parse(testArgs)
 
 	// Should pass on failure
-	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*string]" {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
+	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*[]string]" {
+		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*[]string]", err)
 	}
 }
 
-func TestIntDefaultValuePass(t *testing.T) {
+func TestSelectorDefaultValuePass(t *testing.T) {
 	testArgs := []string{"progname"}
-	testVal := 5150
+	testString := "test list"
 
 	p := NewParser("progname", "Prog description")
 
-	i := p.Int("i", "integer", &Options{Default: testVal})
+	s := p.Selector("s", "string", []string{"opt1", "opt2"}, &Options{Default: testString})
 
 	err := p.Parse(testArgs)
 
@@ -2073,357 +3957,761 @@ func TestIntDefaultValuePass(t *testing.T) {
 	}
 
 	// Should fail if not true
-	if *i != testVal {
-		t.Errorf("expected [%d], got [%d]", testVal, *i)
+	if *s != testString {
+		t.Errorf("expected [%v], got [%v]", testString, *s)
 	}
 }
 
-func TestIntDefaultValueFail(t *testing.T) {
+func TestSelectorDefaultValueFail(t *testing.T) {
 	testArgs := []string{"progname"}
 
 	p := NewParser("progname", "Prog description")
 
-	_ = p.Int("i", "integer", &Options{Default: "fail"})
+	_ = p.Selector("s", "string", []string{"opt1", "opt2"}, &Options{Default: false})
 
 	err := p.Parse(testArgs)
 
 	// Should pass on failure
-	if err == nil || err.Error() != "cannot use default type [string] as value of pointer with type [*int]" {
+	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*string]" {
 		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
 	}
 }
 
-func TestFileDefaultValuePass(t *testing.T) {
-	// Test file location
-	fpath := "./test.tmp"
-	// Create test file
-	f, err := os.Create(fpath)
-	if err != nil {
-		t.Error(err)
-		return
+func TestFloatAddArgumentFail(t *testing.T) {
+	type testCase struct {
+		testName, shortArg, longArg, failureMessage string
 	}
-	f.Close()
-	defer os.Remove(fpath)
-
-	testArgs := []string{"progname"}
+	tt := []testCase{
+		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add Float: short name must not exceed 1 character"},
+		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add Float: long name should be provided"},
+		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add Float: long name flag1 occurs more than once"},
+		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add Float: short name F occurs more than once"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					rezString := fmt.Sprintf("%v", r)
+					if strings.Contains(rezString, tc.failureMessage) == false {
+						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
+					}
+				} else {
+					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
+				}
+			}()
+			p := NewParser("", "description")
+			_ = p.Float("F", "flag1", nil)
+			_ = p.Float(tc.shortArg, tc.longArg, nil)
+		})
+	}
+}
 
-	p := NewParser("", "")
+func TestFloatSimple1(t *testing.T) {
+	pi := "3.1415"
+	piVal := 3.1415
+	testArgs := []string{"progname", "--float1", pi}
 
-	file1 := p.File("f", "file", os.O_RDWR, 0666, &Options{Default: fpath})
+	p := NewParser("", "description")
+	f1 := p.Float("f", "float1", nil)
+	f2 := p.Float("", "float2", nil)
 
-	err = p.Parse(testArgs)
+	err := p.Parse(testArgs)
 	if err != nil {
 		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
-	defer file1.Close()
-}
 
-func TestFileDefaultValueFail(t *testing.T) {
-	// Test file location
-	fpath := "./test.tmp"
-	// Create test file
-	f, err := os.Create(fpath)
-	if err != nil {
-		t.Error(err)
+	if f1 == nil {
+		t.Errorf("Test %s failed with float1 being nil pointer", t.Name())
 		return
 	}
-	f.Close()
-	defer os.Remove(fpath)
-
-	testArgs := []string{"progname"}
 
-	p := NewParser("", "")
+	if f2 == nil {
+		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
+		return
+	}
 
-	file1 := p.File("f", "file", os.O_RDWR, 0666, &Options{Default: true})
+	if *f1 != piVal {
+		t.Errorf("Test %s failed. Want: [%f], got: [%f]", t.Name(), piVal, *f1)
+		return
+	}
 
-	err = p.Parse(testArgs)
-	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*string]" {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
+	if *f2 != 0 {
+		t.Errorf("Test %s failed. Want: [%f], got: [%f]", t.Name(), 0.0, *f2)
+		return
 	}
-	defer file1.Close()
 }
 
-func TestFileListDefaultValuePass(t *testing.T) {
-	testArgs := []string{"progname"}
-	// Test files location
-	fpaths := []string{"./test1.tmp", "./test2.tmp"}
-	// Create test files
-	for _, fpath := range fpaths {
-		f, err := os.Create(fpath)
-		if err != nil {
-			t.Error(err)
-			return
-		}
-		f.Close()
-		defer os.Remove(fpath)
+func TestFloatFail1(t *testing.T) {
+	badArg := "stringNotANumber"
+	testArgs := []string{"progname", "--float1", badArg}
+
+	p := NewParser("", "description")
+	f1 := p.Float("f", "float1", nil)
+
+	err := p.Parse(testArgs)
+	errStr := "[-f|--float1] bad floating point value [stringNotANumber]"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+		return
 	}
 
-	p := NewParser("progname", "Prog description")
+	if f1 == nil {
+		t.Errorf("Test %s failed with float1 being nil pointer", t.Name())
+		return
+	}
 
-	files := p.FileList("f", "float", os.O_RDWR, 0666, &Options{Default: fpaths})
+	if *f1 != 0 {
+		t.Errorf("Test %s failed. Want: [0], got: [%f]", t.Name(), *f1)
+		return
+	}
+}
 
-	err := p.Parse(testArgs)
+func TestIntegerFamilySimple1(t *testing.T) {
+	testArgs := []string{"progname", "--int64", "-0x1F", "--uint", "0o755", "--uint64", "18_446_744_073_709_551_615",
+		"-l", "0b101", "-l", "-9223372036854775808", "-u", "0755,1_000", "-U", "0xFF", "42"}
+
+	p := NewParser("", "description")
+	i64 := p.Int64("", "int64", nil)
+	u := p.Uint("", "uint", nil)
+	u64 := p.Uint64("", "uint64", nil)
+	i64List := p.Int64List("l", "int64-list", nil)
+	uList := p.UintList("u", "uint-list", &Options{Separator: ","})
+	u64List := p.Uint64List("U", "uint64-list", nil)
+	pos := p.Uint64Positional(nil)
+	def := p.Int64("", "default", &Options{Default: int64(-5)})
 
+	err := p.Parse(testArgs)
 	if err != nil {
-		t.Error(err.Error())
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
 	}
-	for i, file := range *files {
-		defer file.Close()
-		testString := "Test"
-		recSlice := make([]byte, 4)
-		_, err = file.WriteString(testString)
-		if err != nil {
-			t.Errorf("Test %s write operation with file: %s failed with error: %s", t.Name(), fpaths[i], err.Error())
-			return
+
+	switch {
+	case *i64 != -31:
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), -31, *i64)
+	case *u != 0755:
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 0755, *u)
+	case *u64 != math.MaxUint64:
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), uint64(math.MaxUint64), *u64)
+	case !reflect.DeepEqual(*i64List, []int64{5, math.MinInt64}):
+		t.Errorf("Test %s failed. Got: [%v]", t.Name(), *i64List)
+	case !reflect.DeepEqual(*uList, []uint{0755, 1000}):
+		t.Errorf("Test %s failed. Got: [%v]", t.Name(), *uList)
+	case !reflect.DeepEqual(*u64List, []uint64{255}):
+		t.Errorf("Test %s failed. Got: [%v]", t.Name(), *u64List)
+	case *pos != 42:
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 42, *pos)
+	case *def != -5:
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), -5, *def)
+	}
+}
+
+func TestIntegerFamilyFail(t *testing.T) {
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--int64", "9223372036854775808"}, errStr: "[--int64] value [9223372036854775808] is out of range [-9223372036854775808, 9223372036854775807]"},
+		{args: []string{"progname", "--int64", "12abc"}, errStr: "[--int64] bad integer value [12abc]"},
+		{args: []string{"progname", "--uint64", "0x1_0000_0000_0000_0000"}, errStr: "[--uint64] value [0x1_0000_0000_0000_0000] is out of range [0, 18446744073709551615]"},
+		{args: []string{"progname", "--uint64", "-1"}, errStr: "[--uint64] bad unsigned integer value [-1]"},
+		{args: []string{"progname", "--uint", "1__0"}, errStr: "[--uint] bad unsigned integer value [1__0]"},
+		{args: []string{"progname", "-l", "1", "-l", "0x"}, errStr: "[-l|--list] bad integer value [0x]"},
+		{args: []string{"progname", "--uint64"}, errStr: "not enough arguments for --uint64"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.Int64("", "int64", nil)
+		p.Uint("", "uint", nil)
+		p.Uint64("", "uint64", nil)
+		p.Int64List("l", "list", nil)
+
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
 		}
-		file.Seek(0, 0)
-		n, err := file.Read(recSlice)
+	}
+}
+
+func TestIntegerFamilyDefaultValueFail(t *testing.T) {
+	p := NewParser("", "description")
+	p.Uint("u", "uint", &Options{Default: 5})
+
+	err := p.Parse([]string{"progname"})
+	errStr := "cannot use default type [int] as value of pointer with type [*uint]"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	}
+}
+
+func TestByteSizeSimple1(t *testing.T) {
+	type testCase struct {
+		value    string
+		expected uint64
+	}
+	testCases := []testCase{
+		{value: "512", expected: 512},
+		{value: "512B", expected: 512},
+		{value: "10k", expected: 10000},
+		{value: "10KB", expected: 10000},
+		{value: "10KiB", expected: 10240},
+		{value: "1.5GiB", expected: 1610612736},
+		{value: "1.5 GB", expected: 1500000000},
+		{value: "2t", expected: 2000000000000},
+		{value: "2Ti", expected: 2 << 40},
+		{value: ".5M", expected: 500000},
+		{value: "1.0001KiB", expected: 1024},
+		{value: "15.999EiB", expected: 18445591152204944769},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		size := p.ByteSize("s", "size", nil)
+
+		err := p.Parse([]string{"progname", "--size", tc.value})
 		if err != nil {
-			t.Errorf("Test %s read operation with file: %s failed with error: %s", t.Name(), fpaths[i], err.Error())
-			return
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
 		}
-		if n != 4 || string(recSlice) != testString {
-			t.Errorf("Test %s failed with file: %s on read operation", t.Name(), fpaths[i])
-			return
+		if *size != tc.expected {
+			t.Errorf("Test %s failed for %s. Want: [%d], got: [%d]", t.Name(), tc.value, tc.expected, *size)
 		}
 	}
-
 }
 
-func TestFloatListDefaultValuePass(t *testing.T) {
-	testArgs := []string{"progname"}
-	testList := []float64{12.0, -10}
+func TestByteSizeFail(t *testing.T) {
+	type testCase struct {
+		value  string
+		errStr string
+	}
+	testCases := []testCase{
+		{value: "10XB", errStr: "[-s|--size] bad byte size value [10XB]"},
+		{value: "-1MB", errStr: "[-s|--size] bad byte size value [-1MB]"},
+		{value: "1.MB", errStr: "[-s|--size] bad byte size value [1.MB]"},
+		{value: "MB", errStr: "[-s|--size] bad byte size value [MB]"},
+		{value: "16EiB", errStr: "[-s|--size] value [16EiB] is out of range [0, 18446744073709551615]"},
+		{value: "18446744073709551616", errStr: "[-s|--size] value [18446744073709551616] is out of range [0, 18446744073709551615]"},
+	}
 
-	p := NewParser("progname", "Prog description")
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.ByteSize("s", "size", nil)
 
-	s := p.FloatList("f", "float", &Options{Default: testList})
+		err := p.Parse([]string{"progname", "--size", tc.value})
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}
 
-	err := p.Parse(testArgs)
+func TestByteSizeDefault(t *testing.T) {
+	p := NewParser("prog", "description")
+	cache := p.ByteSize("c", "cache", &Options{Help: "Cache size", Default: uint64(64 << 20)})
+	p.ByteSize("", "upload", &Options{Help: "Upload limit", Default: uint64(10e6)})
+	p.ByteSize("", "buffer", &Options{Help: "Buffer", Default: uint64(1500)})
+	p.ByteSize("", "small", &Options{Help: "Small", Default: uint64(0)})
 
-	switch {
-	// Should fail on failure
-	case err != nil:
-		t.Error(err.Error())
-	// Should fail if not true
-	case !reflect.DeepEqual(*s, testList):
-		t.Errorf("expected [%v], got [%v]", testList, *s)
+	err := p.Parse([]string{"prog"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	if *cache != 64<<20 {
+		t.Errorf("Test %s failed. Want: [%d], got: [%d]", t.Name(), 64<<20, *cache)
 	}
-}
 
-func TestIntListDefaultValuePass(t *testing.T) {
-	testArgs := []string{"progname"}
-	testList := []int{12, -10}
+	usage := p.Usage(nil)
+	for _, expected := range []string{"[-c|--cache <size>]", "Cache size. Default: 64MiB", "Upload limit. Default: 10MB",
+		"Buffer. Default: 1.46KiB", "Small. Default: 0B"} {
+		if !strings.Contains(usage, expected) {
+			t.Errorf("Test %s failed. Usage does not contain %q:\n%s", t.Name(), expected, usage)
+		}
+	}
+}
 
-	p := NewParser("progname", "Prog description")
+func TestMapSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--label", "team=infra", "-l", "tier=web", "--label=url=http://x/?a=b",
+		"--limit", "cpu=2,mem=512", "--ratio", "a:0.5", "--ratio=b:1e3", "--set", "empty="}
 
-	s := p.IntList("i", "int", &Options{Default: testList})
+	p := NewParser("", "description")
+	labels := p.StringMap("l", "label", nil)
+	limits := p.IntMap("", "limit", &Options{Separator: ","})
+	ratios := p.FloatMap("", "ratio", &Options{KeySeparator: ":"})
+	set := p.StringMap("", "set", nil)
+	env := p.StringMap("", "env", &Options{Default: map[string]string{"HOME": "/root"}})
 
 	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
 
 	switch {
-	// Should fail on failure
-	case err != nil:
-		t.Error(err.Error())
-	// Should fail if not true
-	case !reflect.DeepEqual(*s, testList):
-		t.Errorf("expected [%v], got [%v]", testList, *s)
+	case !reflect.DeepEqual(*labels, map[string]string{"team": "infra", "tier": "web", "url": "http://x/?a=b"}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *labels)
+	case !reflect.DeepEqual(*limits, map[string]int{"cpu": 2, "mem": 512}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *limits)
+	case !reflect.DeepEqual(*ratios, map[string]float64{"a": 0.5, "b": 1000}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *ratios)
+	case !reflect.DeepEqual(*set, map[string]string{"empty": ""}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *set)
+	case !reflect.DeepEqual(*env, map[string]string{"HOME": "/root"}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *env)
 	}
 }
 
-func TestStringListDefaultValuePass(t *testing.T) {
-	testArgs := []string{"progname"}
-	testList := []string{"test", "list"}
-
-	p := NewParser("progname", "Prog description")
-
-	s := p.StringList("s", "string", &Options{Default: testList})
+func TestMapDuplicateKeys(t *testing.T) {
+	type testCase struct {
+		policy   DuplicatePolicy
+		expected map[string]string
+	}
+	testCases := []testCase{
+		{policy: DuplicateLastWins, expected: map[string]string{"a": "2", "b": "3"}},
+		{policy: DuplicateFirstWins, expected: map[string]string{"a": "1", "b": "3"}},
+	}
 
-	err := p.Parse(testArgs)
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		m := p.StringMap("s", "set", &Options{Duplicate: tc.policy})
 
-	switch {
-	// Should fail on failure
-	case err != nil:
-		t.Error(err.Error())
-	// Should fail if not true
-	case !reflect.DeepEqual(*s, testList):
-		t.Errorf("expected [%v], got [%v]", testList, *s)
+		err := p.Parse([]string{"progname", "-s", "a=1", "-s", "b=3", "-s", "a=2"})
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(*m, tc.expected) {
+			t.Errorf("Test %s failed. Want: %v, got: %v", t.Name(), tc.expected, *m)
+		}
 	}
 }
 
-func TestListDefaultValuePass(t *testing.T) {
-	testArgs := []string{"progname"}
-	testList := []string{"test", "list"}
-
-	p := NewParser("progname", "Prog description")
+func TestMapFail(t *testing.T) {
+	validateKey := func(key string) error {
+		if strings.ToLower(key) != key {
+			return errors.New("must be lower case")
+		}
+		return nil
+	}
 
-	s := p.List("s", "string", &Options{Default: testList})
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "-s", "a=1", "-s", "a=2"}, errStr: "[-s|--set] key [a] can only be present once"},
+		{args: []string{"progname", "-s", "a"}, errStr: "[-s|--set] bad key=value pair [a]"},
+		{args: []string{"progname", "-s", "=1"}, errStr: "[-s|--set] empty key in [=1]"},
+		{args: []string{"progname", "-s", "Team=1"}, errStr: "[-s|--set] bad key [Team]: must be lower case"},
+		{args: []string{"progname", "-i", "a=x"}, errStr: "[-i|--int] bad integer value [x]"},
+		{args: []string{"progname", "-f", "a=1.2.3"}, errStr: "[-f|--float] bad floating point value [1.2.3]"},
+		{args: []string{"progname", "-s"}, errStr: "not enough arguments for -s|--set"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.StringMap("s", "set", &Options{ValidateKey: validateKey})
+		p.IntMap("i", "int", nil)
+		p.FloatMap("f", "float", nil)
+
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}
 
-	err := p.Parse(testArgs)
+func TestMapDefault(t *testing.T) {
+	p := NewParser("prog", "description")
+	limits := p.IntMap("l", "limit", &Options{Help: "Limits", Separator: ",", Default: "cpu=1,mem=256"})
+	p.StringMap("", "header", &Options{Help: "Headers", KeySeparator: ":"})
+	p.FloatMap("", "bad", &Options{Default: map[string]int{"a": 1}})
 
-	// Should fail on failure
+	err := p.Parse([]string{"prog", "--bad", "a=1"})
 	if err != nil {
-		t.Error(err.Error())
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	if !reflect.DeepEqual(*limits, map[string]int{"cpu": 1, "mem": 256}) {
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *limits)
 	}
 
-	// Should fail if not true
-	if !reflect.DeepEqual(*s, testList) {
-		t.Errorf("expected [%v], got [%v]", testList, *s)
+	usage := p.Usage(nil)
+	for _, expected := range []string{"[-l|--limit <key>=<value>]", "[--header <key>:<value>]"} {
+		if !strings.Contains(usage, expected) {
+			t.Errorf("Test %s failed. Usage does not contain %q:\n%s", t.Name(), expected, usage)
+		}
 	}
-}
 
-func TestFileListDefaultValueFail(t *testing.T) {
-	testArgs := []string{"progname"}
+	p = NewParser("prog", "description")
+	p.FloatMap("", "bad", &Options{Default: map[string]int{"a": 1}})
+	err = p.Parse([]string{"prog"})
+	errStr := "cannot use default type [map[string]int] as value of pointer with type [*map[string]float64]"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	}
+}
 
-	p := NewParser("progname", "Prog description")
+func TestNetworkSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--listen", "::1", "--cidr", "10.1.2.3/8", "--peer", "[fe80::1]:0",
+		"--endpoint", "HTTPS://example.com/api?x=1", "-a", "10.0.0.1,10.0.0.2", "-n", "fd00::/8",
+		"-p", "example.com:80", "-p", ":65535", "-u", "http://a", "192.168.0.1:22"}
 
-	_ = p.FileList("f", "float", os.O_RDWR, 0666, &Options{Default: false})
+	p := NewParser("", "description")
+	listen := p.IP("", "listen", nil)
+	cidr := p.IPNet("", "cidr", nil)
+	peer := p.HostPort("", "peer", nil)
+	endpoint := p.URL("", "endpoint", []string{"http", "https"}, nil)
+	addrs := p.IPList("a", "addr", &Options{Separator: ","})
+	nets := p.IPNetList("n", "net", nil)
+	peers := p.HostPortList("p", "peers", nil)
+	urls := p.URLList("u", "urls", nil, nil)
+	target := p.HostPortPositional(nil)
+	defIP := p.IP("", "default-ip", &Options{Default: "127.0.0.1"})
+	defURL := p.URL("", "default-url", nil, &Options{Default: "unix:///var/run/app.sock"})
+	defPeers := p.HostPortList("", "default-peers", &Options{Default: []string{"a:1", "b:2"}})
 
 	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
 
-	// Should pass on failure
-	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]string]"
-	if err == nil || err.Error() != failureMessage {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
+	switch {
+	case !listen.Equal(net.IPv6loopback):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *listen)
+	case cidr.String() != "10.0.0.0/8":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), cidr.String())
+	case *peer != "[fe80::1]:0":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *peer)
+	case endpoint.Scheme != "https" || endpoint.Host != "example.com" || endpoint.Query().Get("x") != "1":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), endpoint.String())
+	case len(*addrs) != 2 || !(*addrs)[1].Equal(net.ParseIP("10.0.0.2")):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *addrs)
+	case len(*nets) != 1 || (*nets)[0].String() != "fd00::/8":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *nets)
+	case !reflect.DeepEqual(*peers, []string{"example.com:80", ":65535"}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *peers)
+	case len(*urls) != 1 || (*urls)[0].Host != "a":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *urls)
+	case *target != "192.168.0.1:22":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *target)
+	case !defIP.Equal(net.IPv4(127, 0, 0, 1)):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *defIP)
+	case defURL.Scheme != "unix" || defURL.Path != "/var/run/app.sock":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), defURL.String())
+	case !reflect.DeepEqual(*defPeers, []string{"a:1", "b:2"}):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *defPeers)
 	}
 }
 
-func TestFloatListDefaultValueFail(t *testing.T) {
-	testArgs := []string{"progname"}
-
-	p := NewParser("progname", "Prog description")
+func TestNetworkFail(t *testing.T) {
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--ip", "10.0.0.256"}, errStr: "[--ip] bad IP address [10.0.0.256]"},
+		{args: []string{"progname", "--cidr", "10.0.0.0"}, errStr: "[--cidr] bad CIDR network [10.0.0.0], expected address/prefix length such as 10.0.0.0/8"},
+		{args: []string{"progname", "--cidr", "10.0.0.0/33"}, errStr: "[--cidr] bad CIDR network [10.0.0.0/33], expected address/prefix length such as 10.0.0.0/8"},
+		{args: []string{"progname", "--peer", "example.com"}, errStr: "[--peer] bad host:port value [example.com]: missing port in address"},
+		{args: []string{"progname", "--peer", "::1:80"}, errStr: "[--peer] bad host:port value [::1:80]: too many colons in address"},
+		{args: []string{"progname", "--peer", "exa_mple.com:80"}, errStr: "[--peer] bad host [exa_mple.com] in [exa_mple.com:80]"},
+		{args: []string{"progname", "--peer", "host-:80"}, errStr: "[--peer] bad host [host-] in [host-:80]"},
+		{args: []string{"progname", "--peer", "host:65536"}, errStr: "[--peer] bad port [65536] in [host:65536], expected number from 0 to 65535"},
+		{args: []string{"progname", "--peer", "host:http"}, errStr: "[--peer] bad port [http] in [host:http], expected number from 0 to 65535"},
+		{args: []string{"progname", "--peer", "host:+80"}, errStr: "[--peer] bad port [+80] in [host:+80], expected number from 0 to 65535"},
+		{args: []string{"progname", "--url", "example.com/api"}, errStr: "[--url] bad URL [example.com/api]: missing scheme, such as https://"},
+		{args: []string{"progname", "--url", "http://[::1"}, errStr: "[--url] bad URL [http://[::1]: missing ']' in host"},
+		{args: []string{"progname", "--url", "ftp://example.com"}, errStr: "[--url] URL scheme [ftp] is not one of allowed schemes [http https]"},
+		{args: []string{"progname", "--ips", "10.0.0.1", "--ips", "x"}, errStr: "[--ips] bad IP address [x]"},
+		{args: []string{"progname", "--ip"}, errStr: "not enough arguments for --ip"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.IP("", "ip", nil)
+		p.IPNet("", "cidr", nil)
+		p.HostPort("", "peer", nil)
+		p.URL("", "url", []string{"http", "https"}, nil)
+		p.IPList("", "ips", nil)
+
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}
 
-	_ = p.FloatList("f", "float", &Options{Default: false})
+func TestNetworkDefaultFail(t *testing.T) {
+	type testCase struct {
+		opts   *Options
+		errStr string
+	}
+	testCases := []testCase{
+		{opts: &Options{Default: "localhost"}, errStr: "[--peer] bad host:port value [localhost]: missing port in address"},
+		{opts: &Options{Default: []string{"a:1"}}, errStr: "cannot use default type [[]string] as value of pointer with type [*string]"},
+		{opts: &Options{Default: 80}, errStr: "cannot use default type [int] as value of pointer with type [*string]"},
+	}
 
-	err := p.Parse(testArgs)
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.HostPort("", "peer", tc.opts)
 
-	// Should pass on failure
-	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]float64]"
-	if err == nil || err.Error() != failureMessage {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
+		err := p.Parse([]string{"progname"})
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
 	}
 }
+func TestNetworkDefaultTyped(t *testing.T) {
+	p := NewParser("", "description")
+	ip := p.IP("", "ip", &Options{Default: net.IPv4(10, 0, 0, 1)})
+	cidr := p.IPNet("", "cidr", &Options{Default: net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}})
+	endpoint := p.URL("", "url", nil, &Options{Default: url.URL{Scheme: "https", Host: "example.com"}})
+	ips := p.IPList("", "ips", &Options{Default: []net.IP{net.IPv6loopback}})
 
-func TestIntListDefaultValueFail(t *testing.T) {
-	testArgs := []string{"progname"}
-
-	p := NewParser("progname", "Prog description")
-
-	_ = p.IntList("i", "int", &Options{Default: false})
+	err := p.Parse([]string{"progname"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
 
-	err := p.Parse(testArgs)
+	switch {
+	case !ip.Equal(net.IPv4(10, 0, 0, 1)):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *ip)
+	case cidr.String() != "10.0.0.0/8":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), cidr.String())
+	case endpoint.String() != "https://example.com":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), endpoint.String())
+	case len(*ips) != 1 || !(*ips)[0].Equal(net.IPv6loopback):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *ips)
+	}
 
-	// Should pass on failure
-	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]int]"
-	if err == nil || err.Error() != failureMessage {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
+	p = NewParser("", "description")
+	p.IP("", "ip", &Options{Default: net.IPv4Mask(255, 0, 0, 0)})
+	err = p.Parse([]string{"progname"})
+	errStr := "cannot use default type [net.IPMask] as value of pointer with type [*net.IP]"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
 	}
 }
 
-func TestStringListDefaultValueFail(t *testing.T) {
-	testArgs := []string{"progname"}
+func TestNetworkUsage(t *testing.T) {
+	p := NewParser("prog", "description")
+	p.IP("", "listen", &Options{Help: "Listen address"})
+	p.IPNet("", "cidr", &Options{Help: "Network"})
+	p.HostPort("", "peer", &Options{Help: "Peer"})
+	p.URLList("", "endpoint", nil, &Options{Help: "Endpoints"})
+
+	usage := p.Usage(nil)
+	for _, expected := range []string{"[--listen <ip>]", "[--cidr <cidr>]", "[--peer <host:port>]", "[--endpoint <url>]"} {
+		if !strings.Contains(usage, expected) {
+			t.Errorf("Test %s failed. Usage does not contain %q:\n%s", t.Name(), expected, usage)
+		}
+	}
+}
 
-	p := NewParser("progname", "Prog description")
+func TestRegexpSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--include", `\.go$`, "-e", "_test", "-e", "^vendor/", "--posix", "a|ab"}
 
-	_ = p.StringList("s", "string", &Options{Default: false})
+	p := NewParser("", "description")
+	include := p.Regexp("i", "include", nil)
+	excludes := p.RegexpList("e", "exclude", nil)
+	posix := p.Regexp("", "posix", &Options{POSIX: true})
+	unset := p.Regexp("", "unset", nil)
+	def := p.Regexp("", "default", &Options{Default: "^[a-z]+$"})
 
 	err := p.Parse(testArgs)
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
 
-	// Should pass on failure
-	failureMessage := "cannot use default type [bool] as value of pointer with type [*[]string]"
-	if err == nil || err.Error() != failureMessage {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), failureMessage, err)
+	switch {
+	case *include == nil || !(*include).MatchString("main.go") || (*include).MatchString("main.gox"):
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *include)
+	case len(*excludes) != 2 || (*excludes)[1].String() != "^vendor/":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *excludes)
+	case *posix == nil || (*posix).FindString("ab") != "ab":
+		t.Errorf("Test %s failed. POSIX leftmost-longest match expected, got: %v", t.Name(), *posix)
+	case *unset != nil:
+		t.Errorf("Test %s failed. Want: nil, got: %v", t.Name(), *unset)
+	case *def == nil || (*def).String() != "^[a-z]+$":
+		t.Errorf("Test %s failed. Got: %v", t.Name(), *def)
 	}
 }
 
-func TestListDefaultValueFail(t *testing.T) {
-	testArgs := []string{"progname"}
-
-	p := NewParser("progname", "Prog description")
-
-	_ = p.List("s", "string", &Options{Default: false})
+func TestRegexpFail(t *testing.T) {
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--include", "a("}, errStr: "[-i|--include] error parsing regexp: missing closing ): `a(`"},
+		{args: []string{"progname", "-e", "ok", "-e", "[z-a]"}, errStr: "[-e|--exclude] error parsing regexp: invalid character class range: `z-a`"},
+		{args: []string{"progname", "--posix", `\d+`}, errStr: "[--posix] error parsing regexp: invalid escape sequence: `\\d`"},
+	}
 
-	err := p.Parse(testArgs)
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.Regexp("i", "include", nil)
+		p.RegexpList("e", "exclude", nil)
+		p.Regexp("", "posix", &Options{POSIX: true})
 
-	// Should pass on failure
-	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*[]string]" {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*[]string]", err)
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+			continue
+		}
+		var syntaxErr *syntax.Error
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Test %s failed. Error does not wrap *syntax.Error: %#v", t.Name(), err)
+		}
 	}
 }
 
-func TestSelectorDefaultValuePass(t *testing.T) {
-	testArgs := []string{"progname"}
-	testString := "test list"
+func TestIntRangesSimple1(t *testing.T) {
+	type testCase struct {
+		args     []string
+		expected IntSet
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--shards", "0-3,7"}, expected: IntSet{{0, 3, 1}, {7, 7, 1}}},
+		{args: []string{"progname", "--shards", "10-12,1-5,8,4-6"}, expected: IntSet{{1, 6, 1}, {8, 8, 1}, {10, 12, 1}}},
+		{args: []string{"progname", "--shards", "13-"}, expected: IntSet{{13, 15, 1}}},
+		{args: []string{"progname", "--shards", "0-15:5,3-:6,4-4:3"}, expected: IntSet{{0, 15, 5}, {3, 15, 6}, {4, 4, 1}}},
+		{args: []string{"progname", "--shards", "1-12:5,3,3-5,1-2:2"}, expected: IntSet{{1, 1, 1}, {1, 11, 5}, {3, 5, 1}}},
+		{args: []string{"progname", "-s", "1", "-s", "0-1"}, expected: IntSet{{0, 1, 1}}},
+		{args: []string{"progname"}, expected: IntSet{{2, 3, 1}}},
+	}
 
-	p := NewParser("progname", "Prog description")
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		shards := p.IntRanges("s", "shards", 0, 15, &Options{Default: "2-3"})
 
-	s := p.Selector("s", "string", []string{"opt1", "opt2"}, &Options{Default: testString})
+		err := p.Parse(tc.args)
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(*shards, tc.expected) {
+			t.Errorf("Test %s failed for %v. Want: %v, got: %v", t.Name(), tc.args, tc.expected, *shards)
+		}
+	}
+}
 
-	err := p.Parse(testArgs)
+func TestIntRangesContains(t *testing.T) {
+	p := NewParser("", "description")
+	cpus := p.IntRanges("c", "cpus", -4, maxInt, nil)
 
-	// Should fail on failure
+	err := p.Parse([]string{"progname", "--cpus", "-4--3,10-:1000," + strconv.Itoa(maxInt-1) + "-"})
 	if err != nil {
-		t.Error(err.Error())
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	for _, v := range []int{-4, -3, 10, 1010, 10 + (maxInt-10)/1000*1000, maxInt - 1, maxInt} {
+		if !cpus.Contains(v) {
+			t.Errorf("Test %s failed. %v does not contain %d", t.Name(), *cpus, v)
+		}
+	}
+	for _, v := range []int{-5, -2, 0, 11, 1009, maxInt - 2} {
+		if cpus.Contains(v) {
+			t.Errorf("Test %s failed. %v contains %d", t.Name(), *cpus, v)
+		}
 	}
 
-	// Should fail if not true
-	if *s != testString {
-		t.Errorf("expected [%v], got [%v]", testString, *s)
+	values, complete := cpus.Values(5)
+	if !reflect.DeepEqual(values, []int{-4, -3, 10, 1010, 2010}) || complete {
+		t.Errorf("Test %s failed. Want: [-4 -3 10 1010 2010] false, got: %v %v", t.Name(), values, complete)
 	}
 }
 
-func TestSelectorDefaultValueFail(t *testing.T) {
-	testArgs := []string{"progname"}
-
-	p := NewParser("progname", "Prog description")
-
-	_ = p.Selector("s", "string", []string{"opt1", "opt2"}, &Options{Default: false})
+func TestIntRangesValues(t *testing.T) {
+	p := NewParser("", "description")
+	lines := p.IntRanges("l", "lines", 0, maxInt, &Options{Help: "Lines", Default: "0-2,5-9:4"})
 
-	err := p.Parse(testArgs)
+	err := p.Parse([]string{"progname", "--lines", "8-10,4-:2,1"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	values, complete := lines.Values(6)
+	if !reflect.DeepEqual(values, []int{1, 4, 6, 8, 9, 10}) || complete {
+		t.Errorf("Test %s failed. Want: [1 4 6 8 9 10] false, got: %v %v", t.Name(), values, complete)
+	}
+	if lines.String() != "1,4-"+strconv.Itoa(4+(maxInt-4)/2*2)+":2,8-10" {
+		t.Errorf("Test %s failed. Got: %s", t.Name(), lines.String())
+	}
 
-	// Should pass on failure
-	if err == nil || err.Error() != "cannot use default type [bool] as value of pointer with type [*string]" {
-		t.Errorf("Test %s failed: expected error [%s], got error [%+v]", t.Name(), "cannot use default type [bool] as value of pointer with type [*string]", err)
+	p = NewParser("", "description")
+	lines = p.IntRanges("l", "lines", 0, 15, &Options{Help: "Lines", Default: "0-2,5-9:4"})
+	if err := p.Parse([]string{"progname"}); err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+		return
+	}
+	values, complete = lines.Values(10)
+	if !reflect.DeepEqual(values, []int{0, 1, 2, 5, 9}) || !complete {
+		t.Errorf("Test %s failed. Want: [0 1 2 5 9] true, got: %v %v", t.Name(), values, complete)
+	}
+	values, complete = lines.Values(-1)
+	if len(values) != 0 || complete {
+		t.Errorf("Test %s failed. Want: [] false, got: %v %v", t.Name(), values, complete)
+	}
+	values, complete = IntSet{}.Values(-1)
+	if len(values) != 0 || !complete {
+		t.Errorf("Test %s failed. Want: [] true, got: %v %v", t.Name(), values, complete)
+	}
+	if usage := p.Usage(nil); !strings.Contains(usage, "Default: 0-2,5-9:4") {
+		t.Errorf("Test %s failed. Usage does not contain default value:\n%s", t.Name(), usage)
 	}
 }
 
-func TestFloatAddArgumentFail(t *testing.T) {
+func TestIntRangesFail(t *testing.T) {
 	type testCase struct {
-		testName, shortArg, longArg, failureMessage string
-	}
-	tt := []testCase{
-		testCase{testName: "Long short name", shortArg: "ff", longArg: "flag2", failureMessage: "unable to add Float: short name must not exceed 1 character"},
-		testCase{testName: "Long name not provided", shortArg: "f", longArg: "", failureMessage: "unable to add Float: long name should be provided"},
-		testCase{testName: "Long name twice", shortArg: "f", longArg: "flag1", failureMessage: "unable to add Float: long name flag1 occurs more than once"},
-		testCase{testName: "Short name twice", shortArg: "F", longArg: "flag2", failureMessage: "unable to add Float: short name F occurs more than once"},
-	}
-	for _, tc := range tt {
-		t.Run(tc.testName, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					rezString := fmt.Sprintf("%v", r)
-					if strings.Contains(rezString, tc.failureMessage) == false {
-						t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, tc.failureMessage)
-					}
-				} else {
-					t.Errorf("Test %s failed with no panic, but panic expected with result: %q", t.Name(), tc.failureMessage)
-				}
-			}()
-			p := NewParser("", "description")
-			_ = p.Float("F", "flag1", nil)
-			_ = p.Float(tc.shortArg, tc.longArg, nil)
-		})
+		value  string
+		errStr string
+	}
+	testCases := []testCase{
+		{value: "0-3,x", errStr: "[-s|--shards] bad range [x] at position 5 of [0-3,x], expected N, N-M, N- or N-M:STEP"},
+		{value: "0-3,,7", errStr: "[-s|--shards] bad range [] at position 5 of [0-3,,7], expected N, N-M, N- or N-M:STEP"},
+		{value: "5:2", errStr: "[-s|--shards] bad range [5:2] at position 1 of [5:2], expected N, N-M, N- or N-M:STEP"},
+		{value: "1,2,3-16", errStr: "[-s|--shards] range [3-16] at position 5 of [1,2,3-16] is out of bounds [0, 15]"},
+		{value: "99999999999999999999", errStr: "[-s|--shards] range [99999999999999999999] at position 1 of [99999999999999999999] is out of bounds [0, 15]"},
+		{value: "7-3", errStr: "[-s|--shards] range [7-3] at position 1 of [7-3] starts after it ends"},
+		{value: "0-10:0", errStr: "[-s|--shards] range [0-10:0] at position 1 of [0-10:0] must have positive step"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.IntRanges("s", "shards", 0, 15, nil)
+
+		err := p.Parse([]string{"progname", "--shards", tc.value})
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
 	}
+
+	defer func() {
+		failureMessage := "unable to add IntRanges: min 5 is greater than max 1"
+		if r := recover(); r == nil || fmt.Sprintf("%v", r) != failureMessage {
+			t.Errorf("Test %s failed with panic result: \"%v\". panic result: %q expected", t.Name(), r, failureMessage)
+		}
+	}()
+	p := NewParser("", "description")
+	p.IntRanges("s", "shards", 5, 1, nil)
 }
 
-func TestFloatSimple1(t *testing.T) {
-	pi := "3.1415"
-	piVal := 3.1415
-	testArgs := []string{"progname", "--float1", pi}
+func TestDurationSimple1(t *testing.T) {
+	testArgs := []string{"progname", "--timeout", "1m30s", "-i", "500ms", "-i", "2h,1h", "10s"}
 
 	p := NewParser("", "description")
-	f1 := p.Float("f", "float1", nil)
-	f2 := p.Float("", "float2", nil)
+	timeout := p.Duration("t", "timeout", nil)
+	interval := p.Duration("", "interval", &Options{Default: 5 * time.Second})
+	intervals := p.DurationList("i", "intervals", &Options{Separator: ","})
+	pos := p.DurationPositional(nil)
 
 	err := p.Parse(testArgs)
 	if err != nil {
@@ -2431,50 +4719,175 @@ func TestFloatSimple1(t *testing.T) {
 		return
 	}
 
-	if f1 == nil {
-		t.Errorf("Test %s failed with float1 being nil pointer", t.Name())
-		return
+	switch {
+	case *timeout != 90*time.Second:
+		t.Errorf("Test %s failed. Want: [%v], got: [%v]", t.Name(), 90*time.Second, *timeout)
+	case *interval != 5*time.Second:
+		t.Errorf("Test %s failed. Want: [%v], got: [%v]", t.Name(), 5*time.Second, *interval)
+	case !reflect.DeepEqual(*intervals, []time.Duration{500 * time.Millisecond, 2 * time.Hour, time.Hour}):
+		t.Errorf("Test %s failed. Got: [%v]", t.Name(), *intervals)
+	case *pos != 10*time.Second:
+		t.Errorf("Test %s failed. Want: [%v], got: [%v]", t.Name(), 10*time.Second, *pos)
 	}
+}
 
-	if f2 == nil {
-		t.Errorf("Test %s failed with flag2 being nil pointer", t.Name())
-		return
+func TestDurationExtended(t *testing.T) {
+	type testCase struct {
+		value    string
+		expected time.Duration
 	}
-
-	if *f1 != piVal {
-		t.Errorf("Test %s failed. Want: [%f], got: [%f]", t.Name(), piVal, *f1)
-		return
+	testCases := []testCase{
+		{value: "3d", expected: 72 * time.Hour},
+		{value: "2w", expected: 14 * 24 * time.Hour},
+		{value: "1w2d12h30m", expected: 9*24*time.Hour + 12*time.Hour + 30*time.Minute},
+		{value: "1.5d", expected: 36 * time.Hour},
+		{value: "-1d", expected: -24 * time.Hour},
+		{value: "90s", expected: 90 * time.Second},
 	}
 
-	if *f2 != 0 {
-		t.Errorf("Test %s failed. Want: [%f], got: [%f]", t.Name(), 0.0, *f2)
-		return
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.ExtendedDurations(true)
+		cmd := p.NewCommand("cmd", "description")
+		d := cmd.Duration("d", "duration", nil)
+
+		err := p.Parse([]string{"progname", "cmd", "-d", tc.value})
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if *d != tc.expected {
+			t.Errorf("Test %s failed for %s. Want: [%v], got: [%v]", t.Name(), tc.value, tc.expected, *d)
+		}
 	}
 }
 
-func TestFloatFail1(t *testing.T) {
-	badArg := "stringNotANumber"
-	testArgs := []string{"progname", "--float1", badArg}
+func TestDurationFail(t *testing.T) {
+	type testCase struct {
+		args     []string
+		extended bool
+		errStr   string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "-d", "10"}, errStr: "[-d|--duration] bad duration value [10]"},
+		{args: []string{"progname", "-d", "3d"}, errStr: "[-d|--duration] bad duration value [3d]"},
+		{args: []string{"progname", "-d", "3x"}, extended: true, errStr: "[-d|--duration] bad duration value [3x]"},
+		{args: []string{"progname", "-l", "1s", "-l", "fast"}, errStr: "[-l|--list] bad duration value [fast]"},
+		{args: []string{"progname", "-d"}, errStr: "not enough arguments for -d|--duration"},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.ExtendedDurations(tc.extended)
+		p.Duration("d", "duration", nil)
+		p.DurationList("l", "list", nil)
+
+		err := p.Parse(tc.args)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}
 
+func TestDurationDefaultValueFail(t *testing.T) {
 	p := NewParser("", "description")
-	f1 := p.Float("f", "float1", nil)
+	p.Duration("d", "duration", &Options{Default: 5})
 
-	err := p.Parse(testArgs)
-	errStr := "[-f|--float1] bad floating point value [stringNotANumber]"
+	err := p.Parse([]string{"progname"})
+	errStr := "cannot use default type [int] as value of pointer with type [*time.Duration]"
 	if err == nil || err.Error() != errStr {
 		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
-		return
 	}
+}
+
+func TestDurationUsage(t *testing.T) {
+	p := NewParser("prog", "description")
+	p.Duration("t", "timeout", &Options{Help: "Timeout", Default: 30 * time.Second})
+
+	usage := p.Usage(nil)
+	for _, expected := range []string{"[-t|--timeout <duration>]", "Timeout. Default: 30s"} {
+		if !strings.Contains(usage, expected) {
+			t.Errorf("Test %s failed. Usage does not contain %q:\n%s", t.Name(), expected, usage)
+		}
+	}
+}
 
-	if f1 == nil {
-		t.Errorf("Test %s failed with float1 being nil pointer", t.Name())
-		return
+func TestTimeSimple1(t *testing.T) {
+	fixed := time.Date(2024, 3, 10, 15, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return fixed }
+	defer func() { timeNow = time.Now }()
+
+	est := time.FixedZone("EST", -5*3600)
+	type testCase struct {
+		value    string
+		expected time.Time
+	}
+	testCases := []testCase{
+		{value: "2024-01-02T03:04:05+02:00", expected: time.Date(2024, 1, 2, 1, 4, 5, 0, time.UTC)},
+		{value: "2024-01-02", expected: time.Date(2024, 1, 2, 0, 0, 0, 0, est)},
+		{value: "2024-01-02 03:04", expected: time.Date(2024, 1, 2, 3, 4, 0, 0, est)},
+		{value: "1700000000", expected: time.Unix(1700000000, 0)},
+		{value: "now", expected: fixed},
+		{value: "today", expected: time.Date(2024, 3, 10, 0, 0, 0, 0, est)},
+		{value: "yesterday", expected: time.Date(2024, 3, 9, 0, 0, 0, 0, est)},
+		{value: "tomorrow", expected: time.Date(2024, 3, 11, 0, 0, 0, 0, est)},
+		{value: "-2h", expected: fixed.Add(-2 * time.Hour)},
+		{value: "+1d", expected: fixed.Add(24 * time.Hour)},
+	}
+
+	for _, tc := range testCases {
+		p := NewParser("", "description")
+		p.TimeLocation(est)
+		p.ExtendedDurations(true)
+		since := p.Time("s", "since", []string{"2006-01-02", "2006-01-02 15:04"}, nil)
+
+		err := p.Parse([]string{"progname", "--since", tc.value})
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if !since.Equal(tc.expected) {
+			t.Errorf("Test %s failed for %s. Want: [%v], got: [%v]", t.Name(), tc.value, tc.expected, *since)
+		}
 	}
+}
 
-	if *f1 != 0 {
-		t.Errorf("Test %s failed. Want: [0], got: [%f]", t.Name(), *f1)
+func TestTimeDefault(t *testing.T) {
+	def := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p := NewParser("prog", "description")
+	since := p.Time("s", "since", nil, &Options{Help: "Start time", Default: def})
+
+	err := p.Parse([]string{"prog"})
+	if err != nil {
+		t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
 		return
 	}
+	if !since.Equal(def) {
+		t.Errorf("Test %s failed. Want: [%v], got: [%v]", t.Name(), def, *since)
+	}
+	if usage := p.Usage(nil); !strings.Contains(usage, "[-s|--since <time>]") {
+		t.Errorf("Test %s failed. Usage does not contain time argument:\n%s", t.Name(), usage)
+	}
+}
+
+func TestTimeFail(t *testing.T) {
+	p := NewParser("", "description")
+	p.Time("s", "since", []string{"2006-01-02"}, nil)
+
+	err := p.Parse([]string{"progname", "--since", "last week"})
+	errStr := "[-s|--since] bad time value [last week], expected 2006-01-02T15:04:05Z07:00, 2006-01-02, " +
+		"Unix time in seconds, now, today, yesterday, tomorrow or duration relative to now such as -2h"
+	if err == nil || err.Error() != errStr {
+		t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), errStr, err)
+	}
+
+	p = NewParser("", "description")
+	p.Time("s", "since", nil, nil)
+	err = p.Parse([]string{"progname", "--since", "2024-01-02"})
+	if err == nil || !strings.HasPrefix(err.Error(), "[-s|--since] bad time value [2024-01-02]") {
+		t.Errorf("Test %s expected bad time value error, got [%+v]", t.Name(), err)
+	}
 }
 
 var pUsageString = `test string
@@ -1,11 +1,21 @@
 package argparse
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type arg struct {
@@ -19,26 +29,176 @@ type arg struct {
 	fileFlag int          // File mode to open file with
 	filePerm os.FileMode  // File permissions to set a file
 	selector *[]string    // Used in Selector type to allow to choose only one from list of options
+	layouts  []string     // Used in Time type to parse time with one of layouts
+	schemes  []string     // Used in URL type to allow only specific URL schemes
+	rangeMin int          // Used in IntRanges type as the smallest allowed value
+	rangeMax int          // Used in IntRanges type as the largest allowed value
 	parent   *Command     // Used to get access to specific Command
-	eqChar   bool         // This is used if the command is passed in with an equals char as a seperator
 	argType  ArgumentType // Used to determine which argument type this is
+	nargs    *nargs       // Used by list arguments to consume several values on each occurrence
+
+	shortAliases []string        // Additional short names
+	longAliases  []string        // Additional long names
+	deprecated   map[string]bool // Deprecated aliases (with leading dashes)
+	warned       bool            // Specifies whether deprecation warning has been printed already
+
+	flagSet *flag.FlagSet // Used in GoFlag type to set values of flags imported from flag.FlagSet
+}
+
+// Alias is an additional name of an argument, see Options.Aliases
+type Alias struct {
+	Name       string // Name with leading dashes, "-c" for shorthand name or "--config-dir" for long name
+	Deprecated bool   // Deprecated alias still works, but prints a warning naming the replacement
+}
+
+// IntRange is an inclusive range of integers from Start to End, which contains every Step-th integer,
+// so End is always Start plus a multiple of Step
+type IntRange struct {
+	Start int
+	End   int
+	Step  int
+}
+
+// contains checks if range contains the value
+func (r IntRange) contains(value int) bool {
+	// Differences are computed as unsigned, since they may not fit into int
+	return value >= r.Start && value <= r.End && (uint64(value)-uint64(r.Start))%uint64(r.Step) == 0
+}
+
+// IntSet is a set of integers kept as a list of ranges sorted by start, such as the one returned
+// by Command.IntRanges. Overlapping and adjacent ranges without step are merged, ranges are never
+// expanded into separate integers, so even huge ranges take little memory.
+type IntSet []IntRange
+
+// Contains checks if set contains the value
+func (s IntSet) Contains(value int) bool {
+	for _, r := range s {
+		if r.Start > value {
+			break
+		}
+		if r.contains(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Values returns integers of the set in ascending order, but not more than limit of them.
+// The second returned value is false if set contains more than limit integers. Negative limit is treated as zero.
+func (s IntSet) Values(limit int) ([]int, bool) {
+	if limit < 0 {
+		limit = 0
+	}
+	values := make([]int, 0)
+	for _, r := range s {
+		// One value more than limit is taken to know if there are more of them
+		for i, n := r.Start, 0; n <= limit; n++ {
+			values = append(values, i)
+			if uint64(r.End)-uint64(i) < uint64(r.Step) {
+				break
+			}
+			i += r.Step
+		}
+	}
+	values = mergeInts(values)
+	if len(values) > limit {
+		return values[:limit], false
+	}
+	return values, true
+}
+
+// String returns set in the same format as it is given on command line, such as `0-3,7,10-20:5`
+func (s IntSet) String() string {
+	items := make([]string, len(s))
+	for i, r := range s {
+		switch {
+		case r.Start == r.End:
+			items[i] = strconv.Itoa(r.Start)
+		case r.Step == 1:
+			items[i] = fmt.Sprintf("%d-%d", r.Start, r.End)
+		default:
+			items[i] = fmt.Sprintf("%d-%d:%d", r.Start, r.End, r.Step)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+// nargs defines how many values list argument consumes on each occurrence
+type nargs struct {
+	min int // Minimal number of values
+	max int // Maximal number of values, negative if there is no limit
+}
+
+// parseNArgs - parses Options.NArgs value, see Options for supported formats
+func parseNArgs(value string) (*nargs, error) {
+	switch value {
+	case "+":
+		return &nargs{min: 1, max: -1}, nil
+	case "*":
+		return &nargs{min: 0, max: -1}, nil
+	}
+	if strings.HasSuffix(value, "+") {
+		min, err := strconv.Atoi(strings.TrimSuffix(value, "+"))
+		if err != nil || min < 0 {
+			return nil, fmt.Errorf("bad NArgs value [%s]", value)
+		}
+		return &nargs{min: min, max: -1}, nil
+	}
+	if ind := strings.Index(value, "-"); ind > 0 {
+		min, errMin := strconv.Atoi(value[:ind])
+		max, errMax := strconv.Atoi(value[ind+1:])
+		if errMin != nil || errMax != nil || min < 0 || max < 1 || min > max {
+			return nil, fmt.Errorf("bad NArgs value [%s]", value)
+		}
+		return &nargs{min: min, max: max}, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("bad NArgs value [%s]", value)
+	}
+	return &nargs{min: n, max: n}, nil
 }
 
 // enum used to determine the argument type
 type ArgumentType int
 
 const (
-	Flag        ArgumentType = 0
-	FlagCounter              = 1
-	String                   = 2
-	Int                      = 3
-	Float                    = 4
-	File                     = 5
-	StringList               = 6
-	IntList                  = 7
-	FloatList                = 8
-	FileList                 = 9
-	Selector                 = 10
+	Flag         ArgumentType = 0
+	FlagCounter               = 1
+	String                    = 2
+	Int                       = 3
+	Float                     = 4
+	File                      = 5
+	StringList                = 6
+	IntList                   = 7
+	FloatList                 = 8
+	FileList                  = 9
+	Selector                  = 10
+	GoFlag                    = 11
+	Duration                  = 12
+	DurationList              = 13
+	Time                      = 14
+	Int64                     = 15
+	Uint                      = 16
+	Uint64                    = 17
+	Int64List                 = 18
+	UintList                  = 19
+	Uint64List                = 20
+	ByteSize                  = 21
+	StringMap                 = 22
+	IntMap                    = 23
+	FloatMap                  = 24
+	IP                        = 25
+	IPNet                     = 26
+	HostPort                  = 27
+	URL                       = 28
+	IPList                    = 29
+	IPNetList                 = 30
+	HostPortList              = 31
+	URLList                   = 32
+	Regexp                    = 33
+	RegexpList                = 34
+	IntRanges                 = 35
 )
 
 // Arg interface provides exporting of arg structure, while exposing it
@@ -82,65 +242,307 @@ func (o arg) GetResult() interface{} {
 
 type help struct{}
 
+// setAliases - validates Options.Aliases and stores them as argument's short and long names
+func (o *arg) setAliases() error {
+	if o.GetPositional() {
+		return fmt.Errorf("positional argument cannot have aliases")
+	}
+	o.deprecated = make(map[string]bool)
+	for _, v := range o.opts.Aliases {
+		switch {
+		case len(v.Name) > 2 && strings.HasPrefix(v.Name, "--"):
+			o.longAliases = append(o.longAliases, v.Name[2:])
+		case len(v.Name) > 1 && v.Name[0] == '-' && v.Name[1] != '-':
+			o.shortAliases = append(o.shortAliases, v.Name[1:])
+		default:
+			return fmt.Errorf("bad alias name [%s]", v.Name)
+		}
+		if v.Deprecated {
+			o.deprecated[v.Name] = true
+		}
+	}
+	return nil
+}
+
+// shortNames - returns short name of the argument along with its short aliases
+func (o *arg) shortNames() []string {
+	names := make([]string, 0, len(o.shortAliases)+1)
+	if o.sname != "" {
+		names = append(names, o.sname)
+	}
+	return append(names, o.shortAliases...)
+}
+
+// longNames - returns long name of the argument along with its long aliases
+func (o *arg) longNames() []string {
+	names := make([]string, 0, len(o.longAliases)+1)
+	if o.lname != "" {
+		names = append(names, o.lname)
+	}
+	return append(names, o.longAliases...)
+}
+
+// hasShortName - checks if name is a short name or short alias of the argument
+func (o *arg) hasShortName(name string) bool {
+	for _, v := range o.shortNames() {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isLongName - checks if name (without leading dashes) is exactly one of the argument's long names or long aliases.
+// Names are compared after normalization, see Parser.Normalize.
+func (o *arg) isLongName(name string) bool {
+	for _, v := range o.longNames() {
+		if o.parent.normalizeName(v) == o.parent.normalizeName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// warnDeprecated - prints a warning to the error writer if argument was given using deprecated alias.
+// Warning is printed only once per argument.
+func (o *arg) warnDeprecated(argument string) {
+	if o.warned || len(o.deprecated) == 0 {
+		return
+	}
+	used := ""
+	if strings.HasPrefix(argument, "--") || o.parent.isSingleDashLongName(argument) {
+		name := o.parent.normalizeName(strings.TrimLeft(argument, "-"))
+		matched := make([]string, 0)
+		for _, lname := range o.longNames() {
+			v := o.parent.normalizeName(lname)
+			if v == name {
+				matched = []string{lname}
+				break
+			}
+			if o.parent.allowAbbrev && strings.HasPrefix(v, name) {
+				matched = append(matched, lname)
+			}
+		}
+		// Abbreviation is deprecated only if every long name it could stand for is deprecated
+		for _, lname := range matched {
+			if !o.deprecated["--"+lname] {
+				used = ""
+				break
+			}
+			used = "--" + lname
+		}
+	} else if isShortName(argument) {
+		for _, v := range o.splitShortName(argument) {
+			if o.deprecated["-"+v.name] {
+				used = "-" + v.name
+			}
+		}
+	}
+	if used == "" {
+		return
+	}
+	replacement := "--" + o.lname
+	if !strings.HasPrefix(used, "--") && o.sname != "" {
+		replacement = "-" + o.sname
+	}
+	fmt.Fprintf(o.parent.getErrorWriter(), "warning: %s is deprecated, use %s instead\n", used, replacement)
+	o.warned = true
+}
+
+// matchLongName - checks if argument (without leading dashes) refers to this argument. Besides exact match of the long name,
+// its unique prefix is accepted as well if abbreviations are allowed (see Parser.AllowAbbrev).
+// Returns an error if prefix is ambiguous.
+func (o *arg) matchLongName(argument string) (bool, error) {
+	name := o.parent.normalizeName(argument)
+	prefix := false
+	for _, v := range o.longNames() {
+		lname := o.parent.normalizeName(v)
+		if name == lname {
+			return true, nil
+		}
+		prefix = prefix || strings.HasPrefix(lname, name)
+	}
+	if o.parent == nil || !o.parent.allowAbbrev || !prefix {
+		return false, nil
+	}
+	candidates := make([]string, 0)
+	for current := o.parent; current != nil; current = current.parent {
+		for _, v := range current.args {
+			if v.GetPositional() {
+				continue
+			}
+			for _, lname := range v.longNames() {
+				vname := o.parent.normalizeName(lname)
+				if !strings.HasPrefix(vname, name) {
+					continue
+				}
+				// Exact match of another argument always wins
+				if vname == name {
+					return false, nil
+				}
+				candidate := "--" + v.lname
+				known := false
+				for _, c := range candidates {
+					known = known || c == candidate
+				}
+				if !known {
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+	}
+	if len(candidates) > 1 {
+		return false, fmt.Errorf("ambiguous argument --%s could match %s", argument, strings.Join(candidates, ", "))
+	}
+	return true, nil
+}
+
 // checkLongName if long argumet present.
 // checkLongName - returns the argumet's long name number of occurrences and error.
 // For long name return value is 0 or 1.
-func (o *arg) checkLongName(argument string) int {
+func (o *arg) checkLongName(argument string) (int, error) {
 	// Check for long name only if not empty
 	if o.lname != "" {
+		// Long name given with single dash, if it is allowed
+		if o.parent != nil && o.parent.isSingleDashLongName(argument) && o.isLongName(argument[1:]) {
+			return 1, nil
+		}
 		// If argument begins with "--" and next is not "-" then it is a long name
 		if len(argument) > 2 && strings.HasPrefix(argument, "--") && argument[2] != '-' {
-			if argument[2:] == o.lname {
-				return 1
+			if ok, err := o.matchLongName(argument[2:]); err != nil {
+				return 0, err
+			} else if ok {
+				return 1, nil
 			}
 		}
 	}
 
-	return 0
+	return 0, nil
+}
+
+// shortOption is a single option found inside of a shorthand argument, e.g. `-xvf` holds three of them.
+type shortOption struct {
+	name     string // Short name of the option
+	start    int    // Position in the argument where the option starts
+	end      int    // Position in the argument where the option and its attached value end
+	value    string // Value attached to the option, e.g. `file` in `-ofile`
+	hasValue bool   // Specifies whether value was attached to the option
+}
+
+// lookupShort - finds argument with provided short name among this argument's Command and all its parents.
+// Returns nil if there is no such argument.
+func (o *arg) lookupShort(name string) *arg {
+	if o.hasShortName(name) {
+		return o
+	}
+	for current := o.parent; current != nil; current = current.parent {
+		for _, v := range current.args {
+			if v.hasShortName(name) && !v.GetPositional() {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// splitShortName - splits shorthand argument into separate options the same way POSIX getopt does.
+// Flags could be combined together, while the option that takes a value consumes the rest of the argument
+// as its value (`-ofile`, `-n5`). If the rest is empty, then the value is expected in the following argument.
+// Value starting with "=" is accepted as well (`-o=file`).
+// Options are split by runes, so non-ASCII short names (`-ä`) are supported.
+// If multi-character short names are allowed, then the whole argument is a single option.
+func (o *arg) splitShortName(argument string) []shortOption {
+	// Without combining, the whole argument is a single option with optional value after equals char
+	if o.parent != nil && o.parent.multiCharShort {
+		option := shortOption{name: argument[1:], start: 1, end: len(argument)}
+		if ind := strings.Index(argument, "="); ind > 0 {
+			option.name, option.value, option.hasValue = argument[1:ind], argument[ind+1:], true
+		}
+		return []shortOption{option}
+	}
+	options := make([]shortOption, 0, len(argument)-1)
+	for i, r := range argument[1:] {
+		start := i + 1
+		end := start + utf8.RuneLen(r)
+		option := shortOption{name: string(r), start: start, end: end}
+		if r == '=' && len(options) > 0 {
+			// Value provided to a flag, it is consumed along with the flag
+			last := &options[len(options)-1]
+			last.value, last.hasValue, last.end = argument[end:], true, len(argument)
+			break
+		}
+		if a := o.lookupShort(option.name); a != nil && a.size > 1 {
+			if end < len(argument) {
+				option.value = strings.TrimPrefix(argument[end:], "=")
+				option.hasValue = true
+				option.end = len(argument)
+			}
+			options = append(options, option)
+			break
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// isShortName - checks if argument begins with "-" and next is not "-", meaning it is a shorthand argument
+func isShortName(argument string) bool {
+	return len(argument) > 1 && strings.HasPrefix(argument, "-") && argument[1] != '-'
 }
 
 // checkShortName if argument present.
 // checkShortName - returns the argumet's short name number of occurrences and error.
 // For shorthand argument - 0 if there is no occurrences, or count of occurrences.
-// Shorthand argument with parametr, mast be the only or last in the argument string.
+// Shorthand argument with parameter takes the rest of the argument string as its value,
+// or the following argument if it is the last one in the argument string.
 func (o *arg) checkShortName(argument string) (int, error) {
 	// Check for short name only if not empty
-	if o.sname != "" {
-
-		// If argument begins with "-" and next is not "-" then it is a short name
-		if len(argument) > 1 && strings.HasPrefix(argument, "-") && argument[1] != '-' {
-			count := strings.Count(argument[1:], o.sname)
-			switch {
-			// For args with size 1 (Flag,FlagCounter) multiple shorthand in one argument are allowed
-			case o.size == 1:
-				return count, nil
-			// For args with o.size > 1, shorthand argument is allowed only to complete the sequence of arguments combined into one
-			case o.size > 1:
-				if count > 1 {
-					return count, fmt.Errorf("[%s] argument: The parameter must follow", o.name())
-				}
-				if strings.HasSuffix(argument[1:], o.sname) {
-					return count, nil
-				}
-			//if o.size < 1 - it is an error
-			default:
-				return 0, fmt.Errorf("Argument's size < 1 is not allowed")
+	if len(o.shortNames()) > 0 && isShortName(argument) {
+		// Negative numbers and long names given with single dash are not a set of shorthand arguments
+		if o.parent != nil && (o.parent.isNegativeNumber(argument) || o.parent.isSingleDashLongName(argument)) {
+			return 0, nil
+		}
+		//if o.size < 1 - it is an error
+		if o.size < 1 {
+			return 0, fmt.Errorf("Argument's size < 1 is not allowed")
+		}
+		count := 0
+		for _, v := range o.splitShortName(argument) {
+			if o.hasShortName(v.name) {
+				count++
 			}
 		}
+		return count, nil
 	}
 
 	return 0, nil
 }
 
+// attachedValue - returns value attached to the shorthand argument, e.g. `file` for `-ofile` or `-o=file`.
+// The second returned value specifies whether value was attached.
+func (o *arg) attachedValue(argument string) (string, bool) {
+	if len(o.shortNames()) == 0 || o.size < 2 || !isShortName(argument) {
+		return "", false
+	}
+	if o.parent != nil && o.parent.isSingleDashLongName(argument) {
+		return "", false
+	}
+	for _, v := range o.splitShortName(argument) {
+		if o.hasShortName(v.name) {
+			return v.value, v.hasValue
+		}
+	}
+	return "", false
+}
+
 // check if argument present.
 // check - returns the argument's number of occurrences and error.
 // For long name return value is 0 or 1.
 // For shorthand argument - 0 if there is no occurrences, or count of occurrences.
-// Shorthand argument with parametr, mast be the only or last in the argument string.
 func (o *arg) check(argument string) (int, error) {
-	rez := o.checkLongName(argument)
-	if rez > 0 {
-		return rez, nil
+	rez, err := o.checkLongName(argument)
+	if err != nil || rez > 0 {
+		return rez, err
 	}
 
 	return o.checkShortName(argument)
@@ -154,15 +556,15 @@ func (o *arg) reduceLongName(position int, args *[]string) {
 	argument := (*args)[position]
 	// Check for long name only if not empty
 	if o.lname != "" {
+		if o.parent != nil && o.parent.isSingleDashLongName(argument) && o.isLongName(argument[1:]) {
+			for i := position; i < position+o.reduceSize(); i++ {
+				(*args)[i] = ""
+			}
+		}
 		// If argument begins with "--" and next is not "-" then it is a long name
 		if len(argument) > 2 && strings.HasPrefix(argument, "--") && argument[2] != '-' {
-			if o.eqChar {
-				splitInd := strings.LastIndex(argument, "=")
-				equalArg := []string{argument[:splitInd], argument[splitInd+1:]}
-				argument = equalArg[0]
-			}
-			if argument[2:] == o.lname {
-				for i := position; i < position+o.size; i++ {
+			if ok, _ := o.matchLongName(argument[2:]); ok {
+				for i := position; i < position+o.reduceSize(); i++ {
 					(*args)[i] = ""
 				}
 			}
@@ -173,32 +575,42 @@ func (o *arg) reduceLongName(position int, args *[]string) {
 func (o *arg) reduceShortName(position int, args *[]string) {
 	argument := (*args)[position]
 	// Check for short name only if not empty
-	if o.sname != "" {
-		// If argument begins with "-" and next is not "-" then it is a short name
-		if len(argument) > 1 && strings.HasPrefix(argument, "-") && argument[1] != '-' {
-			// For args with size 1 (Flag,FlagCounter) we allow multiple shorthand in one
-			if o.size == 1 {
-				if strings.Contains(argument[1:], o.sname) {
-					(*args)[position] = strings.Replace(argument, o.sname, "", -1)
-					if (*args)[position] == "-" {
-						(*args)[position] = ""
-					}
-					if o.eqChar {
-						(*args)[position] = ""
-					}
-				}
-				// For all other types it must be separate argument
-			} else {
-				if argument[1:] == o.sname {
-					for i := position; i < position+o.size; i++ {
-						(*args)[i] = ""
-					}
+	if len(o.shortNames()) > 0 && isShortName(argument) {
+		if o.parent != nil && (o.parent.isNegativeNumber(argument) || o.parent.isSingleDashLongName(argument)) {
+			return
+		}
+		options := o.splitShortName(argument)
+		// Remove options from the end, so positions of remaining ones stay valid
+		for i := len(options) - 1; i >= 0; i-- {
+			v := options[i]
+			if !o.hasShortName(v.name) {
+				continue
+			}
+			argument = argument[:v.start] + argument[v.end:]
+			// Value is not attached, so it is the following argument(s)
+			if o.size > 1 && !v.hasValue {
+				for j := position + 1; j < position+o.reduceSize() && j < len(*args); j++ {
+					(*args)[j] = ""
 				}
 			}
 		}
+		if argument == "-" {
+			argument = ""
+		}
+		(*args)[position] = argument
 	}
 }
 
+// reduceSize - returns number of arguments to clear out starting from matched argument.
+// Values of arguments with NArgs are cleared out separately, since their number varies.
+// Arguments with optional value never consume following arguments.
+func (o *arg) reduceSize() int {
+	if o.nargs != nil || o.hasConst() {
+		return 1
+	}
+	return o.size
+}
+
 // clear out already used argument from args at position
 func (o *arg) reduce(position int, args *[]string) {
 	if o.GetPositional() {
@@ -226,159 +638,1081 @@ func (o *arg) parseInt(args []string, argCount int) error {
 		if err != nil {
 			return fmt.Errorf("[%s] bad integer value [%s]", o.name(), args[0])
 		}
-		*o.result.(*int) = val
+		*o.result.(*int) = val
+	}
+	o.parsed = true
+	return nil
+}
+
+// parseSigned - parses signed integer of bitSize bits, accepting base prefixes and underscores same as Go literals
+func (o *arg) parseSigned(value string, bitSize int) (int64, error) {
+	val, err := strconv.ParseInt(value, 0, bitSize)
+	if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+		min := int64(-1) << uint(bitSize-1)
+		return 0, fmt.Errorf("[%s] value [%s] is out of range [%d, %d]", o.name(), value, min, ^min)
+	} else if err != nil {
+		return 0, fmt.Errorf("[%s] bad integer value [%s]", o.name(), value)
+	}
+	return val, nil
+}
+
+// parseUnsigned - parses unsigned integer of bitSize bits, accepting base prefixes and underscores same as Go literals
+func (o *arg) parseUnsigned(value string, bitSize int) (uint64, error) {
+	val, err := strconv.ParseUint(value, 0, bitSize)
+	if e, ok := err.(*strconv.NumError); ok && e.Err == strconv.ErrRange {
+		return 0, fmt.Errorf("[%s] value [%s] is out of range [0, %d]", o.name(), value, uint64(1)<<uint(bitSize)-1)
+	} else if err != nil {
+		return 0, fmt.Errorf("[%s] bad unsigned integer value [%s]", o.name(), value)
+	}
+	return val, nil
+}
+
+func (o *arg) parseInt64(args []string) error {
+	//data of int64 type is for Int64 argument with one integer parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by an integer", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	val, err := o.parseSigned(args[0], 64)
+	if err != nil {
+		return err
+	}
+
+	*o.result.(*int64) = val
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseUint(args []string) error {
+	//data of uint type is for Uint argument with one unsigned integer parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by an unsigned integer", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	val, err := o.parseUnsigned(args[0], strconv.IntSize)
+	if err != nil {
+		return err
+	}
+
+	*o.result.(*uint) = uint(val)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseUint64(args []string) error {
+	//data of uint64 type is for Uint64 argument with one unsigned integer parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by an unsigned integer", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	val, err := o.parseUnsigned(args[0], 64)
+	if err != nil {
+		return err
+	}
+
+	*o.result.(*uint64) = val
+	o.parsed = true
+	return nil
+}
+
+// byteSizeValue matches byte size, such as `10MB` or `1.5 GiB`
+var byteSizeValue = regexp.MustCompile(`^(\d+(?:\.\d+)?|\.\d+) ?([a-zA-Z]*)$`)
+
+// byteSizeUnits - multipliers of byte size suffixes in lower case
+var byteSizeUnits = map[string]uint64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "ki": 1 << 10, "kib": 1 << 10,
+	"m": 1e6, "mb": 1e6, "mi": 1 << 20, "mib": 1 << 20,
+	"g": 1e9, "gb": 1e9, "gi": 1 << 30, "gib": 1 << 30,
+	"t": 1e12, "tb": 1e12, "ti": 1 << 40, "tib": 1 << 40,
+	"p": 1e15, "pb": 1e15, "pi": 1 << 50, "pib": 1 << 50,
+	"e": 1e18, "eb": 1e18, "ei": 1 << 60, "eib": 1 << 60,
+}
+
+// byteSizeNames - byte size suffixes used to format byte size, from the largest to the smallest
+var byteSizeNames = []struct {
+	name string
+	size uint64
+}{
+	{"EiB", 1 << 60}, {"EB", 1e18}, {"PiB", 1 << 50}, {"PB", 1e15}, {"TiB", 1 << 40}, {"TB", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9}, {"MiB", 1 << 20}, {"MB", 1e6}, {"KiB", 1 << 10}, {"kB", 1e3},
+}
+
+// formatByteSize - formats byte size in human-readable form, such as `64MiB`. The largest suffix which
+// represents size exactly is used, otherwise size is rounded to two decimal places of the largest IEC suffix.
+func formatByteSize(size uint64) string {
+	for _, v := range byteSizeNames {
+		if size >= v.size && size%v.size == 0 {
+			return strconv.FormatUint(size/v.size, 10) + v.name
+		}
+	}
+	for _, v := range byteSizeNames {
+		if size >= v.size && strings.HasSuffix(v.name, "iB") {
+			value := strconv.FormatFloat(float64(size)/float64(v.size), 'f', 2, 64)
+			return strings.TrimRight(strings.TrimRight(value, "0"), ".") + v.name
+		}
+	}
+	return strconv.FormatUint(size, 10) + "B"
+}
+
+func (o *arg) parseByteSize(args []string) error {
+	//data of uint64 type is for ByteSize argument with one byte size parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a byte size", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	match := byteSizeValue.FindStringSubmatch(args[0])
+	if match == nil {
+		return fmt.Errorf("[%s] bad byte size value [%s]", o.name(), args[0])
+	}
+	unit, ok := byteSizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return fmt.Errorf("[%s] bad byte size value [%s]", o.name(), args[0])
+	}
+	// Exact arithmetic makes sure that decimal values are not rounded and overflow is detected
+	size, _ := new(big.Rat).SetString(match[1])
+	size.Mul(size, new(big.Rat).SetInt(new(big.Int).SetUint64(unit)))
+	bytes := new(big.Int).Quo(size.Num(), size.Denom())
+	if !bytes.IsUint64() {
+		return fmt.Errorf("[%s] value [%s] is out of range [0, %d]", o.name(), args[0], uint64(math.MaxUint64))
+	}
+
+	*o.result.(*uint64) = bytes.Uint64()
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseBool(args []string) error {
+	//data of bool type is for Flag argument, which can be explicitly set using equals char (`-flag=false`)
+	//if single dash long names are allowed, otherwise value is ignored
+	val := true
+	if len(args) > 0 && o.parent != nil && o.parent.singleDashLong {
+		var err error
+		if val, err = strconv.ParseBool(args[0]); err != nil {
+			return fmt.Errorf("[%s] bad boolean value [%s]", o.name(), args[0])
+		}
+	}
+	*o.result.(*bool) = val
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseFloat(args []string) error {
+	//data of float64 type is for Float argument with one float parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a floating point number", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	val, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return fmt.Errorf("[%s] bad floating point value [%s]", o.name(), args[0])
+	}
+
+	*o.result.(*float64) = val
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseString(args []string) error {
+	//data of string type is for String argument with one string parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a string", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	value := args[0]
+	// Selector case
+	if o.selector != nil {
+		match := false
+		for _, v := range *o.selector {
+			if o.parent.normalizeName(args[0]) == o.parent.normalizeName(v) {
+				// Value is stored as it was defined
+				value = v
+				match = true
+			}
+		}
+		if !match {
+			return fmt.Errorf("bad value for [%s]. Allowed values are %v", o.name(), *o.selector)
+		}
+	}
+
+	*o.result.(*string) = value
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseFile(args []string) error {
+	//data of os.File type is for File argument with one file name parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a path to file", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	f, err := os.OpenFile(args[0], o.fileFlag, o.filePerm)
+	if err != nil {
+		return err
+	}
+
+	// File is replaced by the following occurrence of the argument
+	if o.parsed {
+		if err := o.result.(*os.File).Close(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	*o.result.(*os.File) = *f
+	o.parsed = true
+	return nil
+}
+
+// checkListSize - checks that list argument got acceptable number of values.
+// Without NArgs each occurrence of list argument takes exactly one value.
+func (o *arg) checkListSize(args []string, value string) error {
+	min, max := 1, 1
+	if o.nargs != nil {
+		min, max = o.nargs.min, o.nargs.max
+	}
+	switch {
+	case len(args) < min && min == 1:
+		return fmt.Errorf("[%s] must be followed by %s", o.name(), value)
+	case len(args) < min:
+		return fmt.Errorf("[%s] must be followed by at least %d values", o.name(), min)
+	case max >= 0 && len(args) > max:
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+	return nil
+}
+
+// extendedDuration matches days and weeks in duration, e.g. `3d` or `1.5w`
+var extendedDuration = regexp.MustCompile(`(\d+\.?\d*|\.\d+)([dw])`)
+
+// parseDuration - parses duration using time.ParseDuration. If extended is true, days (`d`) and weeks (`w`)
+// are allowed as well and are converted to hours before parsing.
+func parseDuration(value string, extended bool) (time.Duration, error) {
+	if extended {
+		value = extendedDuration.ReplaceAllStringFunc(value, func(s string) string {
+			hours, _ := strconv.ParseFloat(s[:len(s)-1], 64)
+			hours *= 24
+			if s[len(s)-1] == 'w' {
+				hours *= 7
+			}
+			return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+		})
+	}
+	return time.ParseDuration(value)
+}
+
+func (o *arg) parseDuration(args []string) error {
+	//data of time.Duration type is for Duration argument with one duration parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a duration", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	val, err := parseDuration(args[0], o.parent.extendedDur)
+	if err != nil {
+		return fmt.Errorf("[%s] bad duration value [%s]", o.name(), args[0])
+	}
+
+	*o.result.(*time.Duration) = val
+	o.parsed = true
+	return nil
+}
+
+// relativeDays - values of Time argument meaning midnight of a day, as number of days from today
+var relativeDays = map[string]int{"today": 0, "yesterday": -1, "tomorrow": 1}
+
+// parseTime - parses time using one of layouts, Unix time in seconds or time relative to now.
+// Returns false if value matches none of them.
+func parseTime(value string, layouts []string, loc *time.Location, extended bool) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+	if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(sec, 0).In(loc), true
+	}
+	now := timeNow().In(loc)
+	if value == "now" {
+		return now, true
+	}
+	if days, ok := relativeDays[value]; ok {
+		y, m, d := now.Date()
+		return time.Date(y, m, d+days, 0, 0, 0, 0, loc), true
+	}
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		if d, err := parseDuration(value, extended); err == nil {
+			return now.Add(d), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (o *arg) parseTime(args []string) error {
+	//data of time.Time type is for Time argument with one time parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a time", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	loc := o.parent.timeLocation
+	if loc == nil {
+		loc = time.Local
+	}
+	val, ok := parseTime(args[0], o.layouts, loc, o.parent.extendedDur)
+	if !ok {
+		return fmt.Errorf("[%s] bad time value [%s], expected %s, Unix time in seconds, now, today, yesterday, "+
+			"tomorrow or duration relative to now such as -2h", o.name(), args[0], strings.Join(o.layouts, ", "))
+	}
+
+	*o.result.(*time.Time) = val
+	o.parsed = true
+	return nil
+}
+
+// splitSeparated - splits value on separator. Separator is taken literally if it is escaped with backslash
+// or if it is inside of single or double quotes wrapping a whole element, such as `'a,b',c`.
+// Such quotes and backslashes escaping separator are removed from result, while any other quotes and
+// backslashes are kept as is, so `C:\dir,O'Brien` results in `C:\dir` and `O'Brien`.
+func splitSeparated(value string, separator string) []string {
+	result := make([]string, 0)
+	var current strings.Builder
+	elementStart := true
+	for i := 0; i < len(value); {
+		if elementStart && (value[i] == '"' || value[i] == '\'') {
+			if end := closingQuote(value[i+1:], value[i], separator); end >= 0 {
+				result = append(result, value[i+1:i+1+end])
+				i += end + 2
+				if i == len(value) {
+					return result
+				}
+				i += len(separator)
+				continue
+			}
+		}
+		elementStart = false
+		switch {
+		case strings.HasPrefix(value[i:], "\\"+separator):
+			current.WriteString(separator)
+			i += 1 + len(separator)
+		case strings.HasPrefix(value[i:], separator):
+			result = append(result, current.String())
+			current.Reset()
+			elementStart = true
+			i += len(separator)
+		default:
+			current.WriteByte(value[i])
+			i++
+		}
+	}
+	return append(result, current.String())
+}
+
+// closingQuote - returns index of quote in value which is followed by separator or the end of value,
+// or -1 if there is no such quote
+func closingQuote(value string, quote byte, separator string) int {
+	for i := 0; i < len(value); i++ {
+		if value[i] == quote && (i+1 == len(value) || strings.HasPrefix(value[i+1:], separator)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitValues - splits each of values on Options.Separator if it was provided
+func (o *arg) splitValues(args []string) []string {
+	if o.opts == nil || o.opts.Separator == "" {
+		return args
+	}
+	values := make([]string, 0, len(args))
+	for _, v := range args {
+		values = append(values, splitSeparated(v, o.opts.Separator)...)
+	}
+	return values
+}
+
+func (o *arg) parseStringList(args []string) error {
+	//data of []string type is for List and StringList argument with set of string parameters
+	if err := o.checkListSize(args, "a string"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	*o.result.(*[]string) = append(*o.result.(*[]string), args...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseIntList(args []string) error {
+	//data of []int type is for IntList argument with set of int parameters
+	if err := o.checkListSize(args, "an integer"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]int, 0, len(args))
+	for _, v := range args {
+		val, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("[%s] bad integer value [%s]", o.name(), v)
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]int) = append(*o.result.(*[]int), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseInt64List(args []string) error {
+	//data of []int64 type is for Int64List argument with set of integer parameters
+	if err := o.checkListSize(args, "an integer"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]int64, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseSigned(v, 64)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]int64) = append(*o.result.(*[]int64), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseUintList(args []string) error {
+	//data of []uint type is for UintList argument with set of unsigned integer parameters
+	if err := o.checkListSize(args, "an unsigned integer"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]uint, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseUnsigned(v, strconv.IntSize)
+		if err != nil {
+			return err
+		}
+		values = append(values, uint(val))
+	}
+	*o.result.(*[]uint) = append(*o.result.(*[]uint), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseUint64List(args []string) error {
+	//data of []uint64 type is for Uint64List argument with set of unsigned integer parameters
+	if err := o.checkListSize(args, "an unsigned integer"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]uint64, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseUnsigned(v, 64)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]uint64) = append(*o.result.(*[]uint64), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseFloatList(args []string) error {
+	//data of []float64 type is for FloatList argument with set of int parameters
+	if err := o.checkListSize(args, "a floating point number"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]float64, 0, len(args))
+	for _, v := range args {
+		val, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("[%s] bad floating point value [%s]", o.name(), v)
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]float64) = append(*o.result.(*[]float64), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseDurationList(args []string) error {
+	//data of []time.Duration type is for DurationList argument with set of duration parameters
+	if err := o.checkListSize(args, "a duration"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]time.Duration, 0, len(args))
+	for _, v := range args {
+		val, err := parseDuration(v, o.parent.extendedDur)
+		if err != nil {
+			return fmt.Errorf("[%s] bad duration value [%s]", o.name(), v)
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]time.Duration) = append(*o.result.(*[]time.Duration), values...)
+	o.parsed = true
+	return nil
+}
+
+// keySeparator - returns separator of key and value of map argument
+func (o *arg) keySeparator() string {
+	if o.opts != nil && o.opts.KeySeparator != "" {
+		return o.opts.KeySeparator
+	}
+	return "="
+}
+
+// parseMap - parses `key=value` pairs into map argument, converting values using parseValue
+func (o *arg) parseMap(args []string, parseValue func(value string) (interface{}, error)) error {
+	separator := o.keySeparator()
+	if err := o.checkListSize(args, "a key"+separator+"value pair"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	result := reflect.ValueOf(o.result).Elem()
+	if result.IsNil() {
+		result.Set(reflect.MakeMap(result.Type()))
+	}
+	for _, v := range args {
+		pair := strings.SplitN(v, separator, 2)
+		if len(pair) != 2 {
+			return fmt.Errorf("[%s] bad key%svalue pair [%s]", o.name(), separator, v)
+		}
+		key := pair[0]
+		if key == "" {
+			return fmt.Errorf("[%s] empty key in [%s]", o.name(), v)
+		}
+		if o.opts != nil && o.opts.ValidateKey != nil {
+			if err := o.opts.ValidateKey(key); err != nil {
+				return fmt.Errorf("[%s] bad key [%s]: %w", o.name(), key, err)
+			}
+		}
+		value, err := parseValue(pair[1])
+		if err != nil {
+			return err
+		}
+		if result.MapIndex(reflect.ValueOf(key)).IsValid() {
+			switch o.duplicatePolicy() {
+			case DuplicateLastWins:
+			case DuplicateFirstWins:
+				continue
+			default:
+				return fmt.Errorf("[%s] key [%s] can only be present once", o.name(), key)
+			}
+		}
+		result.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+	}
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseStringMap(args []string) error {
+	//data of map[string]string type is for StringMap argument with set of key=value parameters
+	return o.parseMap(args, func(value string) (interface{}, error) {
+		return value, nil
+	})
+}
+
+func (o *arg) parseIntMap(args []string) error {
+	//data of map[string]int type is for IntMap argument with set of key=value parameters
+	return o.parseMap(args, func(value string) (interface{}, error) {
+		val, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] bad integer value [%s]", o.name(), value)
+		}
+		return val, nil
+	})
+}
+
+func (o *arg) parseFloatMap(args []string) error {
+	//data of map[string]float64 type is for FloatMap argument with set of key=value parameters
+	return o.parseMap(args, func(value string) (interface{}, error) {
+		val, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] bad floating point value [%s]", o.name(), value)
+		}
+		return val, nil
+	})
+}
+
+// parseIPValue - parses IPv4 or IPv6 address
+func (o *arg) parseIPValue(value string) (net.IP, error) {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("[%s] bad IP address [%s]", o.name(), value)
+	}
+	return ip, nil
+}
+
+// parseIPNetValue - parses network in CIDR notation, such as `10.0.0.0/8` or `fd00::/8`
+func (o *arg) parseIPNetValue(value string) (net.IPNet, error) {
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return net.IPNet{}, fmt.Errorf("[%s] bad CIDR network [%s], expected address/prefix length such as 10.0.0.0/8",
+			o.name(), value)
+	}
+	return *ipNet, nil
+}
+
+// isHostname - checks if host is valid host name according to RFC 1123
+func isHostname(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseHostPortValue - parses host and numeric port, such as `example.com:80`, `[::1]:80` or `:80`
+func (o *arg) parseHostPortValue(value string) (string, error) {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		if e, ok := err.(*net.AddrError); ok {
+			err = errors.New(e.Err)
+		}
+		return "", fmt.Errorf("[%s] bad host:port value [%s]: %s", o.name(), value, err.Error())
+	}
+	if host != "" && net.ParseIP(host) == nil && !isHostname(host) {
+		return "", fmt.Errorf("[%s] bad host [%s] in [%s]", o.name(), host, value)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || port != strconv.FormatUint(n, 10) {
+		return "", fmt.Errorf("[%s] bad port [%s] in [%s], expected number from 0 to 65535", o.name(), port, value)
+	}
+	return value, nil
+}
+
+// parseURLValue - parses absolute URL, checking its scheme if allowed schemes were provided
+func (o *arg) parseURLValue(value string) (url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		if e, ok := err.(*url.Error); ok {
+			err = e.Err
+		}
+		return url.URL{}, fmt.Errorf("[%s] bad URL [%s]: %s", o.name(), value, err.Error())
+	}
+	if u.Scheme == "" {
+		return url.URL{}, fmt.Errorf("[%s] bad URL [%s]: missing scheme, such as https://", o.name(), value)
+	}
+	if len(o.schemes) > 0 {
+		allowed := false
+		for _, v := range o.schemes {
+			if strings.EqualFold(v, u.Scheme) {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return url.URL{}, fmt.Errorf("[%s] URL scheme [%s] is not one of allowed schemes %v", o.name(), u.Scheme, o.schemes)
+		}
 	}
-	o.parsed = true
-	return nil
+	return *u, nil
 }
 
-func (o *arg) parseBool(args []string) error {
-	//data of bool type is for Flag argument
-	*o.result.(*bool) = true
+func (o *arg) parseIP(args []string) error {
+	//data of net.IP type is for IP argument with one IP address parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by an IP address", o.name())
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	}
+
+	val, err := o.parseIPValue(args[0])
+	if err != nil {
+		return err
+	}
+
+	*o.result.(*net.IP) = val
 	o.parsed = true
 	return nil
 }
 
-func (o *arg) parseFloat(args []string) error {
-	//data of float64 type is for Float argument with one float parameter
+func (o *arg) parseIPNet(args []string) error {
+	//data of net.IPNet type is for IPNet argument with one CIDR network parameter
 	if len(args) < 1 {
-		return fmt.Errorf("[%s] must be followed by a floating point number", o.name())
+		return fmt.Errorf("[%s] must be followed by a CIDR network", o.name())
 	}
 	if len(args) > 1 {
 		return fmt.Errorf("[%s] followed by too many arguments", o.name())
 	}
 
-	val, err := strconv.ParseFloat(args[0], 64)
+	val, err := o.parseIPNetValue(args[0])
 	if err != nil {
-		return fmt.Errorf("[%s] bad floating point value [%s]", o.name(), args[0])
+		return err
 	}
 
-	*o.result.(*float64) = val
+	*o.result.(*net.IPNet) = val
 	o.parsed = true
 	return nil
 }
 
-func (o *arg) parseString(args []string) error {
-	//data of string type is for String argument with one string parameter
+func (o *arg) parseHostPort(args []string) error {
+	//data of string type is for HostPort argument with one host:port parameter
 	if len(args) < 1 {
-		return fmt.Errorf("[%s] must be followed by a string", o.name())
+		return fmt.Errorf("[%s] must be followed by a host:port", o.name())
 	}
 	if len(args) > 1 {
 		return fmt.Errorf("[%s] followed by too many arguments", o.name())
 	}
 
-	// Selector case
-	if o.selector != nil {
-		match := false
-		for _, v := range *o.selector {
-			if args[0] == v {
-				match = true
-			}
-		}
-		if !match {
-			return fmt.Errorf("bad value for [%s]. Allowed values are %v", o.name(), *o.selector)
-		}
+	val, err := o.parseHostPortValue(args[0])
+	if err != nil {
+		return err
 	}
 
-	*o.result.(*string) = args[0]
+	*o.result.(*string) = val
 	o.parsed = true
 	return nil
 }
 
-func (o *arg) parseFile(args []string) error {
-	//data of os.File type is for File argument with one file name parameter
+func (o *arg) parseURL(args []string) error {
+	//data of url.URL type is for URL argument with one URL parameter
 	if len(args) < 1 {
-		return fmt.Errorf("[%s] must be followed by a path to file", o.name())
+		return fmt.Errorf("[%s] must be followed by a URL", o.name())
 	}
 	if len(args) > 1 {
 		return fmt.Errorf("[%s] followed by too many arguments", o.name())
 	}
 
-	f, err := os.OpenFile(args[0], o.fileFlag, o.filePerm)
+	val, err := o.parseURLValue(args[0])
 	if err != nil {
 		return err
 	}
 
-	*o.result.(*os.File) = *f
+	*o.result.(*url.URL) = val
 	o.parsed = true
 	return nil
 }
 
-func (o *arg) parseStringList(args []string) error {
-	//data of []string type is for List and StringList argument with set of string parameters
+func (o *arg) parseIPList(args []string) error {
+	//data of []net.IP type is for IPList argument with set of IP address parameters
+	if err := o.checkListSize(args, "an IP address"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]net.IP, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseIPValue(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]net.IP) = append(*o.result.(*[]net.IP), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseIPNetList(args []string) error {
+	//data of []net.IPNet type is for IPNetList argument with set of CIDR network parameters
+	if err := o.checkListSize(args, "a CIDR network"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]net.IPNet, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseIPNetValue(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]net.IPNet) = append(*o.result.(*[]net.IPNet), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseHostPortList(args []string) error {
+	//data of []string type is for HostPortList argument with set of host:port parameters
+	if err := o.checkListSize(args, "a host:port"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]string, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseHostPortValue(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]string) = append(*o.result.(*[]string), values...)
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseURLList(args []string) error {
+	//data of []url.URL type is for URLList argument with set of URL parameters
+	if err := o.checkListSize(args, "a URL"); err != nil {
+		return err
+	}
+	args = o.splitValues(args)
+
+	values := make([]url.URL, 0, len(args))
+	for _, v := range args {
+		val, err := o.parseURLValue(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
+	}
+	*o.result.(*[]url.URL) = append(*o.result.(*[]url.URL), values...)
+	o.parsed = true
+	return nil
+}
+
+// compileRegexp - compiles regular expression, using POSIX syntax if Options.POSIX is set
+func (o *arg) compileRegexp(expr string) (*regexp.Regexp, error) {
+	compile := regexp.Compile
+	if o.opts != nil && o.opts.POSIX {
+		compile = regexp.CompilePOSIX
+	}
+	re, err := compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", o.name(), err)
+	}
+	return re, nil
+}
+
+func (o *arg) parseRegexp(args []string) error {
+	//data of *regexp.Regexp type is for Regexp argument with one regular expression parameter
 	if len(args) < 1 {
-		return fmt.Errorf("[%s] must be followed by a string", o.name())
+		return fmt.Errorf("[%s] must be followed by a regular expression", o.name())
 	}
 	if len(args) > 1 {
 		return fmt.Errorf("[%s] followed by too many arguments", o.name())
 	}
 
-	*o.result.(*[]string) = append(*o.result.(*[]string), args[0])
+	val, err := o.compileRegexp(args[0])
+	if err != nil {
+		return err
+	}
+
+	*o.result.(**regexp.Regexp) = val
 	o.parsed = true
 	return nil
 }
 
-func (o *arg) parseIntList(args []string) error {
-	//data of []int type is for IntList argument with set of int parameters
-	switch {
-	case len(args) < 1:
-		return fmt.Errorf("[%s] must be followed by an integer", o.name())
-	case len(args) > 1:
-		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+func (o *arg) parseRegexpList(args []string) error {
+	//data of []*regexp.Regexp type is for RegexpList argument with set of regular expression parameters
+	if err := o.checkListSize(args, "a regular expression"); err != nil {
+		return err
 	}
+	args = o.splitValues(args)
 
-	val, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("[%s] bad integer value [%s]", o.name(), args[0])
+	values := make([]*regexp.Regexp, 0, len(args))
+	for _, v := range args {
+		val, err := o.compileRegexp(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, val)
 	}
-	*o.result.(*[]int) = append(*o.result.(*[]int), val)
+	*o.result.(*[]*regexp.Regexp) = append(*o.result.(*[]*regexp.Regexp), values...)
 	o.parsed = true
 	return nil
 }
 
-func (o *arg) parseFloatList(args []string) error {
-	//data of []float64 type is for FloatList argument with set of int parameters
-	switch {
-	case len(args) < 1:
-		return fmt.Errorf("[%s] must be followed by a floating point number", o.name())
-	case len(args) > 1:
+// intRangeSyntax matches single range of IntRanges, such as `7`, `0-3`, `5-` or `0-100:10`
+var intRangeSyntax = regexp.MustCompile(`^(-?\d+)(?:(-)(-?\d+)?(?::(\d+))?)?$`)
+
+// parseIntRanges - parses comma separated ranges, which can be unsorted and overlap.
+// Open-ended range (`5-`) ends at the largest allowed value.
+func (o *arg) parseIntRanges(value string) ([]IntRange, error) {
+	ranges := make([]IntRange, 0)
+	position := 1
+	for _, item := range strings.Split(value, ",") {
+		match := intRangeSyntax.FindStringSubmatch(item)
+		if match == nil {
+			return nil, fmt.Errorf("[%s] bad range [%s] at position %d of [%s], expected N, N-M, N- or N-M:STEP",
+				o.name(), item, position, value)
+		}
+		start, errStart := strconv.Atoi(match[1])
+		end, errEnd := start, error(nil)
+		if match[3] != "" {
+			end, errEnd = strconv.Atoi(match[3])
+		} else if match[2] != "" {
+			end = o.rangeMax
+		}
+		step, errStep := 1, error(nil)
+		if match[4] != "" {
+			step, errStep = strconv.Atoi(match[4])
+		}
+		switch {
+		case errStart != nil || errEnd != nil || errStep != nil || start < o.rangeMin || end > o.rangeMax:
+			return nil, fmt.Errorf("[%s] range [%s] at position %d of [%s] is out of bounds [%d, %d]",
+				o.name(), item, position, value, o.rangeMin, o.rangeMax)
+		case start > end:
+			return nil, fmt.Errorf("[%s] range [%s] at position %d of [%s] starts after it ends",
+				o.name(), item, position, value)
+		case step < 1:
+			return nil, fmt.Errorf("[%s] range [%s] at position %d of [%s] must have positive step",
+				o.name(), item, position, value)
+		}
+		// Range ends at its last value, differences are computed as unsigned, since they may not fit into int
+		end = start + int((uint64(end)-uint64(start))/uint64(step)*uint64(step))
+		if start == end {
+			step = 1
+		}
+		ranges = append(ranges, IntRange{Start: start, End: end, Step: step})
+		position += len(item) + 1
+	}
+	return ranges, nil
+}
+
+// mergeInts - sorts values and removes duplicates
+func mergeInts(values []int) []int {
+	sort.Ints(values)
+	result := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// maxInt is the largest value of int
+const maxInt = int(^uint(0) >> 1)
+
+// mergeRanges - sorts ranges by start and merges overlapping and adjacent ranges without step
+func mergeRanges(ranges []IntRange) IntSet {
+	sort.Slice(ranges, func(i, j int) bool {
+		if ranges[i].Start != ranges[j].Start {
+			return ranges[i].Start < ranges[j].Start
+		}
+		return ranges[i].End < ranges[j].End
+	})
+	result := make(IntSet, 0, len(ranges))
+	last := -1 // Index of the last range without step in result
+	for _, r := range ranges {
+		if r.Step == 1 && last >= 0 && (result[last].End == maxInt || r.Start <= result[last].End+1) {
+			if r.End > result[last].End {
+				result[last].End = r.End
+			}
+			continue
+		}
+		if len(result) > 0 && result[len(result)-1] == r {
+			continue
+		}
+		result = append(result, r)
+		if r.Step == 1 {
+			last = len(result) - 1
+		}
+	}
+	return result
+}
+
+func (o *arg) parseIntSet(args []string) error {
+	//data of IntSet type is for IntRanges argument with comma separated ranges parameter
+	if len(args) < 1 {
+		return fmt.Errorf("[%s] must be followed by a list of ranges", o.name())
+	}
+	if len(args) > 1 {
 		return fmt.Errorf("[%s] followed by too many arguments", o.name())
 	}
 
-	val, err := strconv.ParseFloat(args[0], 64)
+	ranges, err := o.parseIntRanges(args[0])
 	if err != nil {
-		return fmt.Errorf("[%s] bad floating point value [%s]", o.name(), args[0])
+		return err
 	}
-	*o.result.(*[]float64) = append(*o.result.(*[]float64), val)
+
+	// Ranges of all occurrences of the argument are merged
+	*o.result.(*IntSet) = mergeRanges(append(*o.result.(*IntSet), ranges...))
 	o.parsed = true
 	return nil
 }
 
 func (o *arg) parseFileList(args []string) error {
 	//data of []os.File type is for FileList argument with set of int parameters
-	switch {
-	case len(args) < 1:
-		return fmt.Errorf("[%s] must be followed by a path to file", o.name())
-	case len(args) > 1:
-		return fmt.Errorf("[%s] followed by too many arguments", o.name())
+	if err := o.checkListSize(args, "a path to file"); err != nil {
+		return err
 	}
-	f, err := os.OpenFile(args[0], o.fileFlag, o.filePerm)
-	if err != nil {
-		//if one of FileList's file opening have been failed, close all other in this list
-		errs := make([]string, 0, len(*o.result.(*[]os.File)))
-		for _, f := range *o.result.(*[]os.File) {
-			if err := f.Close(); err != nil {
-				//almost unreal, but what if another process closed this file
-				errs = append(errs, err.Error())
+	args = o.splitValues(args)
+	for _, v := range args {
+		f, err := os.OpenFile(v, o.fileFlag, o.filePerm)
+		if err != nil {
+			//if one of FileList's file opening have been failed, close all other in this list
+			errs := make([]string, 0, len(*o.result.(*[]os.File)))
+			for _, f := range *o.result.(*[]os.File) {
+				if err := f.Close(); err != nil {
+					//almost unreal, but what if another process closed this file
+					errs = append(errs, err.Error())
+				}
 			}
+			if len(errs) > 0 {
+				err = fmt.Errorf("while handling error: %v, other errors occured: %#v", err.Error(), errs)
+			}
+			*o.result.(*[]os.File) = []os.File{}
+			return err
 		}
-		if len(errs) > 0 {
-			err = fmt.Errorf("while handling error: %v, other errors occured: %#v", err.Error(), errs)
+		*o.result.(*[]os.File) = append(*o.result.(*[]os.File), *f)
+	}
+	o.parsed = true
+	return nil
+}
+
+func (o *arg) parseGoFlag(args []string) error {
+	//data of flag.Value type is for GoFlag argument, value is set through flag.FlagSet so it knows flag was set
+	value := "true" // Boolean flags do not require value
+	if o.size > 1 || len(args) > 0 {
+		if len(args) < 1 {
+			return fmt.Errorf("[%s] must be followed by a value", o.name())
 		}
-		*o.result.(*[]os.File) = []os.File{}
-		return err
+		if len(args) > 1 {
+			return fmt.Errorf("[%s] followed by too many arguments", o.name())
+		}
+		value = args[0]
+	}
+	if err := o.flagSet.Set(o.lname, value); err != nil {
+		return fmt.Errorf("[%s] bad value [%s]: %s", o.name(), value, err.Error())
 	}
-	*o.result.(*[]os.File) = append(*o.result.(*[]os.File), *f)
 	o.parsed = true
 	return nil
 }
@@ -387,6 +1721,7 @@ func (o *arg) parseFileList(args []string) error {
 // Possibly extend to allow user overriding
 var exit func(int) = os.Exit
 var print func(...interface{}) (int, error) = fmt.Println
+var timeNow func() time.Time = time.Now
 
 func (o *arg) parseSomeType(args []string, argCount int) error {
 	var err error
@@ -401,20 +1736,76 @@ func (o *arg) parseSomeType(args []string, argCount int) error {
 		err = o.parseBool(args)
 	case *int:
 		err = o.parseInt(args, argCount)
+	case *int64:
+		err = o.parseInt64(args)
+	case *uint:
+		err = o.parseUint(args)
+	case *uint64:
+		if o.argType == ByteSize {
+			err = o.parseByteSize(args)
+		} else {
+			err = o.parseUint64(args)
+		}
 	case *float64:
 		err = o.parseFloat(args)
+	case *time.Duration:
+		err = o.parseDuration(args)
+	case *time.Time:
+		err = o.parseTime(args)
 	case *string:
-		err = o.parseString(args)
+		if o.argType == HostPort {
+			err = o.parseHostPort(args)
+		} else {
+			err = o.parseString(args)
+		}
+	case *net.IP:
+		err = o.parseIP(args)
+	case *net.IPNet:
+		err = o.parseIPNet(args)
+	case *url.URL:
+		err = o.parseURL(args)
 	case *os.File:
 		err = o.parseFile(args)
 	case *[]string:
-		err = o.parseStringList(args)
+		if o.argType == HostPortList {
+			err = o.parseHostPortList(args)
+		} else {
+			err = o.parseStringList(args)
+		}
+	case *[]net.IP:
+		err = o.parseIPList(args)
+	case *[]net.IPNet:
+		err = o.parseIPNetList(args)
+	case *[]url.URL:
+		err = o.parseURLList(args)
+	case *IntSet:
+		err = o.parseIntSet(args)
+	case **regexp.Regexp:
+		err = o.parseRegexp(args)
+	case *[]*regexp.Regexp:
+		err = o.parseRegexpList(args)
 	case *[]int:
 		err = o.parseIntList(args)
+	case *[]int64:
+		err = o.parseInt64List(args)
+	case *[]uint:
+		err = o.parseUintList(args)
+	case *[]uint64:
+		err = o.parseUint64List(args)
 	case *[]float64:
 		err = o.parseFloatList(args)
+	case *[]time.Duration:
+		err = o.parseDurationList(args)
 	case *[]os.File:
 		err = o.parseFileList(args)
+	case *map[string]string:
+		err = o.parseStringMap(args)
+	case *map[string]int:
+		err = o.parseIntMap(args)
+	case *map[string]float64:
+		err = o.parseFloatMap(args)
+	case flag.Value:
+		err = o.parseGoFlag(args)
 	default:
 		err = fmt.Errorf("unsupported type [%t]", o.result)
 	}
@@ -429,15 +1820,82 @@ func (o *arg) parsePositional(arg string) error {
 	return nil
 }
 
+// hasConst - checks if argument has optional value, which means Options.ConstValue was provided
+func (o *arg) hasConst() bool {
+	return o.opts != nil && o.opts.ConstValue != nil && !o.GetPositional()
+}
+
+// takesDuration - checks if argument takes durations or times relative to now as values
+func (o *arg) takesDuration() bool {
+	switch o.argType {
+	case Duration, DurationList, Time:
+		return true
+	}
+	return false
+}
+
+// checkConst - checks that Options.ConstValue can be used with the argument
+func (o *arg) checkConst() error {
+	switch o.argType {
+	case String, Int, Float, Selector, Duration:
+	default:
+		return fmt.Errorf("ConstValue is only supported by String, Int, Float, Duration and Selector arguments")
+	}
+	if reflect.TypeOf(o.result) != reflect.PtrTo(reflect.TypeOf(o.opts.ConstValue)) {
+		return fmt.Errorf("cannot use ConstValue type [%T] as value of pointer with type [%T]", o.opts.ConstValue, o.result)
+	}
+	if o.selector != nil {
+		for _, v := range *o.selector {
+			if v == o.opts.ConstValue {
+				return nil
+			}
+		}
+		return fmt.Errorf("ConstValue [%v] is not one of allowed values %v", o.opts.ConstValue, *o.selector)
+	}
+	return nil
+}
+
+// duplicatePolicy - returns what happens when argument is present more than once
+func (o *arg) duplicatePolicy() DuplicatePolicy {
+	switch o.argType {
+	case String, Int, Float, Selector, File, Duration, Time, Int64, Uint, Uint64, ByteSize, StringMap, IntMap, FloatMap,
+		IP, IPNet, HostPort, URL, Regexp:
+	default:
+		return DuplicateError
+	}
+	if o.opts != nil && o.opts.Duplicate != DuplicateDefault {
+		return o.opts.Duplicate
+	}
+	if o.parent != nil && o.parent.onDuplicate != DuplicateDefault {
+		return o.parent.onDuplicate
+	}
+	return DuplicateError
+}
+
 func (o *arg) parse(args []string, argCount int) error {
-	// If unique do not allow more than one time
+	// If unique do not allow more than one time, unless duplicate policy allows it
 	if o.unique && (o.parsed || argCount > 1) {
-		return fmt.Errorf("[%s] can only be present once", o.name())
+		switch o.duplicatePolicy() {
+		case DuplicateLastWins:
+		case DuplicateFirstWins:
+			if o.parsed {
+				return o.parseDiscarded(args, argCount)
+			}
+		default:
+			return fmt.Errorf("[%s] can only be present once", o.name())
+		}
+	}
+
+	// Argument with optional value given without value
+	if len(args) == 0 && o.hasConst() {
+		reflect.ValueOf(o.result).Elem().Set(reflect.ValueOf(o.opts.ConstValue))
+		o.parsed = true
+		return nil
 	}
 
 	// If validation function provided -- execute, on error return immediately
 	if o.opts != nil && o.opts.Validate != nil {
-		err := o.opts.Validate(args)
+		err := o.opts.Validate(o.splitValues(args))
 		if err != nil {
 			return fmt.Errorf("[%s] %w", o.name(), err)
 		}
@@ -445,6 +1903,40 @@ func (o *arg) parse(args []string, argCount int) error {
 	return o.parseSomeType(args, argCount)
 }
 
+// parseDiscarded - parses value of argument repeated with DuplicateFirstWins, so bad values are reported
+// the same way as for the first occurrence, and then restores the first value
+func (o *arg) parseDiscarded(args []string, argCount int) error {
+	result := reflect.ValueOf(o.result).Elem()
+	first := reflect.New(result.Type()).Elem()
+	first.Set(result)
+
+	o.parsed = false
+	err := o.parse(args, argCount)
+	// File opened for discarded value is not used
+	if f, ok := o.result.(*os.File); ok && err == nil {
+		err = f.Close()
+	}
+	result.Set(first)
+	o.parsed = true
+	return err
+}
+
+// checkValue - runs Options.Check on the value of argument, if argument was present or got a default value
+func (o *arg) checkValue() error {
+	if o.opts == nil || o.opts.Check == nil || (!o.parsed && o.opts.Default == nil) {
+		return nil
+	}
+	value := o.result
+	// Flags imported from flag.FlagSet are checked with their flag.Value, which is not always a pointer
+	if v := reflect.ValueOf(o.result); o.argType != GoFlag && v.Kind() == reflect.Ptr {
+		value = v.Elem().Interface()
+	}
+	if err := o.opts.Check(value); err != nil {
+		return fmt.Errorf("[%s] %w", o.name(), err)
+	}
+	return nil
+}
+
 func (o *arg) name() string {
 	if o.GetPositional() {
 		return o.lname
@@ -460,9 +1952,43 @@ func (o *arg) name() string {
 	return name
 }
 
+// metavar - returns name of the argument's value used in usage, Options.Metavar or e.g. `FILE` for `--file`
+func (o *arg) metavar() string {
+	if o.opts != nil && o.opts.Metavar != "" {
+		return o.opts.Metavar
+	}
+	return strings.ToUpper(strings.Replace(o.lname, "-", "_", -1))
+}
+
+// nargsUsage - returns usage of values for argument with NArgs, e.g. `FILE [FILE ...]`
+func (o *arg) nargsUsage() string {
+	values := make([]string, 0, o.nargs.min+1)
+	for i := 0; i < o.nargs.min; i++ {
+		values = append(values, o.metavar())
+	}
+	if o.nargs.max < 0 || o.nargs.max > o.nargs.min {
+		values = append(values, "["+o.metavar()+" ...]")
+	}
+	return strings.Join(values, " ")
+}
+
 func (o *arg) usage() string {
 	var result string
 	result = o.name()
+	if o.hasConst() {
+		result = result + "[=" + o.metavar() + "]"
+		if o.opts.Required == false {
+			result = "[" + result + "]"
+		}
+		return result
+	}
+	if o.nargs != nil {
+		result = result + " " + o.nargsUsage()
+		if o.opts == nil || o.opts.Required == false {
+			result = "[" + result + "]"
+		}
+		return result
+	}
 	switch o.result.(type) {
 	case *bool:
 		break
@@ -471,18 +1997,50 @@ func (o *arg) usage() string {
 		if !isFlagCounter {
 			result = result + " <integer>"
 		}
+	case *int64, *uint, *uint64:
+		if o.argType == ByteSize {
+			result = result + " <size>"
+		} else {
+			result = result + " <integer>"
+		}
 	case *float64:
 		result = result + " <float>"
+	case *time.Duration:
+		result = result + " <duration>"
+	case *time.Time:
+		result = result + " <time>"
 	case *string:
 		if o.selector != nil {
 			result = result + " (" + strings.Join(*o.selector, "|") + ")"
+		} else if o.argType == HostPort {
+			result = result + " <host:port>"
 		} else {
 			result = result + " \"<value>\""
 		}
 	case *os.File:
 		result = result + " <file>"
 	case *[]string:
-		result = result + " \"<value>\"" + " [" + result + " \"<value>\" ...]"
+		if o.argType == HostPortList {
+			result = result + " <host:port>"
+		} else {
+			result = result + " \"<value>\"" + " [" + result + " \"<value>\" ...]"
+		}
+	case *net.IP, *[]net.IP:
+		result = result + " <ip>"
+	case *net.IPNet, *[]net.IPNet:
+		result = result + " <cidr>"
+	case *url.URL, *[]url.URL:
+		result = result + " <url>"
+	case **regexp.Regexp, *[]*regexp.Regexp:
+		result = result + " <regexp>"
+	case *IntSet:
+		result = result + " <ranges>"
+	case *map[string]string, *map[string]int, *map[string]float64:
+		result = result + " <key>" + o.keySeparator() + "<value>"
+	case flag.Value:
+		if name, _ := flag.UnquoteUsage(o.flagSet.Lookup(o.lname)); name != "" {
+			result = result + " <" + name + ">"
+		}
 	default:
 		break
 	}
@@ -496,8 +2054,21 @@ func (o *arg) getHelpMessage() string {
 	message := ""
 	if len(o.opts.Help) > 0 {
 		message += o.opts.Help
+		aliases := make([]string, 0, len(o.opts.Aliases))
+		for _, v := range o.opts.Aliases {
+			if !v.Deprecated {
+				aliases = append(aliases, v.Name)
+			}
+		}
+		if len(aliases) > 0 {
+			message += ". Aliases: " + strings.Join(aliases, ", ")
+		}
 		if !o.opts.Required && o.opts.Default != nil {
-			message += fmt.Sprintf(". Default: %v", o.opts.Default)
+			defaultValue := o.opts.Default
+			if size, ok := defaultValue.(uint64); ok && o.argType == ByteSize {
+				defaultValue = formatByteSize(size)
+			}
+			message += fmt.Sprintf(". Default: %v", defaultValue)
 		}
 	}
 	return message
@@ -520,9 +2091,13 @@ func (o *arg) setDefaultFile() error {
 
 // setDefaultFiles - gets list of default os.File objects based on provided list of default filenames strings
 func (o *arg) setDefaultFiles() error {
-	// In case of FileList we should get []string as default value
+	// In case of FileList we should get []string as default value, or a string with separated values
 	var files []os.File
-	if fileNames, ok := o.opts.Default.([]string); ok {
+	fileNames, ok := o.opts.Default.([]string)
+	if v, isString := o.opts.Default.(string); isString && o.opts.Separator != "" {
+		fileNames, ok = o.splitValues([]string{v}), true
+	}
+	if ok {
 		files = make([]os.File, 0, len(fileNames))
 		for _, v := range fileNames {
 			f, err := os.OpenFile(v, o.fileFlag, o.filePerm)
@@ -550,12 +2125,78 @@ func (o *arg) setDefaultFiles() error {
 	return nil
 }
 
+// withoutNArgs - calls parse function with Options.NArgs disabled, since default values are not given
+// as a number of separate values, so NArgs does not apply to them
+func (o *arg) withoutNArgs(parse func() error) error {
+	nargs := o.nargs
+	o.nargs = nil
+	defer func() { o.nargs = nargs }()
+	return parse()
+}
+
+// setDefaultSeparated - sets default value of list argument provided as a single string with separated values
+func (o *arg) setDefaultSeparated(value string) error {
+	err := o.withoutNArgs(func() error {
+		return o.parseSomeType([]string{value}, 1)
+	})
+	o.parsed = false
+	return err
+}
+
+// setDefaultParsed - sets default value given as a string (or a list of strings for list argument),
+// which is parsed and validated same as a value given on command line. Default value can also be given
+// already parsed, such as net.IP for IP argument.
+func (o *arg) setDefaultParsed() error {
+	var values []string
+	switch v := o.opts.Default.(type) {
+	case string:
+		values = []string{v}
+	case []string:
+		switch o.argType {
+		case IPList, IPNetList, HostPortList, URLList, RegexpList:
+			values = v
+		default:
+			return fmt.Errorf("cannot use default type [%T] as value of pointer with type [%T]", o.opts.Default, o.result)
+		}
+	default:
+		if reflect.TypeOf(o.result) != reflect.PtrTo(reflect.TypeOf(o.opts.Default)) {
+			return fmt.Errorf("cannot use default type [%T] as value of pointer with type [%T]", o.opts.Default, o.result)
+		}
+		reflect.ValueOf(o.result).Elem().Set(reflect.ValueOf(o.opts.Default))
+		return nil
+	}
+	err := o.withoutNArgs(func() error {
+		for _, v := range values {
+			if err := o.parseSomeType([]string{v}, 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	o.parsed = false
+	return err
+}
+
 // setDefault - if no value getted for specific argument, set default value, if provided
 func (o *arg) setDefault() error {
 	// Only set default if it was not parsed, and default value was defined
 	if !o.parsed && o.opts != nil && o.opts.Default != nil {
+		switch o.argType {
+		case IP, IPNet, HostPort, URL, IPList, IPNetList, HostPortList, URLList, Regexp, RegexpList, IntRanges:
+			return o.setDefaultParsed()
+		}
+		// Default value of list argument given as a string with separated values
+		if v, ok := o.opts.Default.(string); ok && o.opts.Separator != "" {
+			switch o.result.(type) {
+			case *[]string, *[]int, *[]float64, *[]time.Duration, *[]int64, *[]uint, *[]uint64,
+				*map[string]string, *map[string]int, *map[string]float64:
+				return o.setDefaultSeparated(v)
+			}
+		}
 		switch o.result.(type) {
-		case *bool, *int, *float64, *string, *[]bool, *[]int, *[]float64, *[]string:
+		case *bool, *int, *float64, *string, *time.Duration, *time.Time, *int64, *uint, *uint64,
+			*[]bool, *[]int, *[]float64, *[]string, *[]time.Duration, *[]int64, *[]uint, *[]uint64,
+			*map[string]string, *map[string]int, *map[string]float64:
 			if reflect.TypeOf(o.result) != reflect.PtrTo(reflect.TypeOf(o.opts.Default)) {
 				return fmt.Errorf("cannot use default type [%T] as value of pointer with type [%T]", o.opts.Default, o.result)
 			}
@@ -2,9 +2,18 @@ package argparse
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
+// negativeNumber matches arguments that look like negative numbers, e.g. `-5` or `-.5`
+var negativeNumber = regexp.MustCompile(`^-\d+$|^-\d*\.\d+$`)
+
+// negativeDuration matches arguments that look like negative durations, e.g. `-2h` or `-1h30m`
+var negativeDuration = regexp.MustCompile(`^-(\d*\.?\d+[a-zµ]+)+$`)
+
 func (o *Command) help(sname, lname string) {
 	result := &help{}
 
@@ -29,9 +38,19 @@ func (o *Command) addArg(a *arg) error {
 	if a.lname == "" {
 		return fmt.Errorf("long name should be provided")
 	}
-	// short name could be provided and must not exceed 1 character
-	if len(a.sname) > 1 {
-		return fmt.Errorf("short name must not exceed 1 character")
+	if a.opts != nil && len(a.opts.Aliases) > 0 {
+		if err := a.setAliases(); err != nil {
+			return err
+		}
+	}
+	// short name could be provided and must not exceed 1 character, unless multi-character short names are allowed
+	for _, sname := range a.shortNames() {
+		if utf8.RuneCountInString(sname) > 1 && !o.multiCharShort {
+			return fmt.Errorf("short name must not exceed 1 character")
+		}
+		if strings.ContainsAny(sname, "-=") {
+			return fmt.Errorf("short name %s must not contain \"-\" or \"=\"", sname)
+		}
 	}
 	// Search parents for overlapping commands and fail if any
 	current := o
@@ -39,11 +58,16 @@ func (o *Command) addArg(a *arg) error {
 		if current.args != nil {
 			for _, v := range current.args {
 				if a.lname != "help" || a.sname != "h" {
-					if a.sname != "" && a.sname == v.sname {
-						return fmt.Errorf("short name %s occurs more than once", a.sname)
+					for _, sname := range a.shortNames() {
+						if v.hasShortName(sname) {
+							return fmt.Errorf("short name %s occurs more than once", sname)
+						}
 					}
-					if a.lname == v.lname {
-						return fmt.Errorf("long name %s occurs more than once", a.lname)
+					// Long names are normalized, so `--dry_run` cannot be added next to `--dry-run` if they match
+					for _, lname := range a.longNames() {
+						if v.isLongName(lname) {
+							return fmt.Errorf("long name %s occurs more than once", lname)
+						}
 					}
 				}
 			}
@@ -52,9 +76,34 @@ func (o *Command) addArg(a *arg) error {
 	}
 	a.parent = o
 
+	if a.opts != nil && a.opts.NArgs != "" {
+		switch a.argType {
+		case StringList, IntList, FloatList, FileList, DurationList, Int64List, UintList, Uint64List,
+			StringMap, IntMap, FloatMap, IPList, IPNetList, HostPortList, URLList, RegexpList:
+		default:
+			return fmt.Errorf("NArgs is only supported by list arguments")
+		}
+		n, err := parseNArgs(a.opts.NArgs)
+		if err != nil {
+			return err
+		}
+		a.nargs = n
+	}
+
+	if a.argType == IntRanges && a.rangeMin > a.rangeMax {
+		return fmt.Errorf("min %d is greater than max %d", a.rangeMin, a.rangeMax)
+	}
+
+	if a.opts != nil && a.opts.ConstValue != nil {
+		if err := a.checkConst(); err != nil {
+			return err
+		}
+	}
+
 	if a.GetPositional() {
 		switch a.argType { // Secondary guard
-		case Flag, FlagCounter, StringList, IntList, FloatList, FileList:
+		case Flag, FlagCounter, IntList, FloatList, FileList, DurationList, Int64List, UintList, Uint64List,
+			StringMap, IntMap, FloatMap, IPList, IPNetList, HostPortList, URLList, RegexpList:
 			return fmt.Errorf("argument type cannot be positional")
 		}
 		a.sname = ""
@@ -66,6 +115,108 @@ func (o *Command) addArg(a *arg) error {
 	return nil
 }
 
+// isArgument - checks if input argument looks like an argument name (and not like a value)
+func (o *Command) isArgument(argument string) bool {
+	return len(argument) > 1 && strings.HasPrefix(argument, "-") && !o.isNegativeNumber(argument)
+}
+
+// countValues - returns number of values following argument at position that belong to argument with NArgs.
+// Values are consumed until the next argument, "--" or the end of arguments. "--" itself is consumed by parseArguments.
+func (o *arg) countValues(position int, args []string) int {
+	count := 0
+	for i := position + 1; i < len(args); i++ {
+		if o.nargs.max >= 0 && count >= o.nargs.max {
+			break
+		}
+		// Empty string is an argument that has already been consumed
+		if args[i] == "" || args[i] == "--" {
+			break
+		}
+		if o.parent.isArgument(args[i]) && !(o.takesDuration() && negativeDuration.MatchString(args[i])) {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// isSingleDashLongName - checks if argument is a long name given with single dash (`-verbose` or `-config=x`)
+// of any argument available to the Command. This is only possible if single dash long names are allowed.
+func (o *Command) isSingleDashLongName(argument string) bool {
+	if !o.singleDashLong || !isShortName(argument) {
+		return false
+	}
+	name := strings.SplitN(argument[1:], "=", 2)[0]
+	for current := o; current != nil; current = current.parent {
+		for _, v := range current.args {
+			if !v.GetPositional() && v.isLongName(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNegativeNumber - checks if argument looks like a negative number and must be treated as a value
+// rather than as a shorthand argument
+func (o *Command) isNegativeNumber(argument string) bool {
+	if !negativeNumber.MatchString(argument) {
+		return false
+	}
+	switch o.negativeNumbers {
+	case NegativeNumbersAsValues:
+		return true
+	case NegativeNumbersAsOptions:
+		return false
+	}
+	// Any argument with a digit as short name makes negative numbers look like shorthand arguments
+	for current := o; current != nil; current = current.parent {
+		for _, v := range current.args {
+			for _, sname := range v.shortNames() {
+				if unicode.IsDigit([]rune(sname)[0]) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// isDurationValue - checks if argument at position is a negative duration given as value of preceding
+// Duration, DurationList or Time argument, such as `-2h` in `--since -2h`. Such values are never treated
+// as shorthand arguments, regardless of NegativeNumbersMode.
+func (o *Command) isDurationValue(position int, args []string) bool {
+	if !negativeDuration.MatchString(args[position]) {
+		return false
+	}
+	// Argument with NArgs can be followed by several durations
+	i := position - 1
+	for i >= 0 && negativeDuration.MatchString(args[i]) {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	for current := o; current != nil; current = current.parent {
+		for _, v := range current.args {
+			if v.GetPositional() || !v.takesDuration() {
+				continue
+			}
+			if cnt, _ := v.check(args[i]); cnt == 0 {
+				continue
+			}
+			if _, ok := v.attachedValue(args[i]); ok || v.hasConst() {
+				return false
+			}
+			if v.nargs != nil {
+				return v.nargs.max < 0 || position-i <= v.nargs.max
+			}
+			return position-i < v.size
+		}
+	}
+	return false
+}
+
 //parseSubCommands - Parses subcommands if any
 func (o *Command) parseSubCommands(args *[]string) error {
 	if o.commands != nil && len(o.commands) > 0 {
@@ -108,10 +259,12 @@ func (o *Command) parsePositionals(inputArgs *[]string) error {
 				continue
 			}
 			if err := oarg.parsePositional(arg); err != nil {
-				return err
+				return o.originError(err, j, *inputArgs)
 			}
 			oarg.reduce(j, inputArgs)
-			break // Positionals can only occur once
+			if oarg.argType != StringList {
+				break // Positionals can only occur once, except list which collects all remaining values
+			}
 		}
 		// positional was unsatisfiable, use the default
 		if !oarg.parsed {
@@ -120,6 +273,9 @@ func (o *Command) parsePositionals(inputArgs *[]string) error {
 				return err
 			}
 		}
+		if err := oarg.checkValue(); err != nil {
+			return err
+		}
 	}
 	for _, c := range o.commands {
 		if c.happened { // presumption of only one sub-command happening
@@ -129,6 +285,116 @@ func (o *Command) parsePositionals(inputArgs *[]string) error {
 	return nil
 }
 
+// splitEqualChar - splits argument given with equals char (`--name=value`) into name and value.
+// Since value and even long name itself could contain equals char, every equals char is tried from left to right
+// until the name matches the argument. Returns number of occurrences of the argument, which is 0 if it did not match.
+func (o *arg) splitEqualChar(argument string) ([]string, int, error) {
+	for i := 0; i < len(argument); i++ {
+		if argument[i] != '=' {
+			continue
+		}
+		equalArg := []string{argument[:i], argument[i+1:]}
+		if cnt, err := o.check(equalArg[0]); err != nil || cnt > 0 {
+			return equalArg, cnt, err
+		}
+	}
+	return nil, 0, nil
+}
+
+// skipValues - returns number of values following argument at position that are consumed by it,
+// without actually parsing them. Unknown arguments are assumed to take no values.
+func (o *Command) skipValues(position int, args []string) int {
+	argument := args[position]
+	for current := o; current != nil; current = current.parent {
+		for _, v := range current.args {
+			if v.GetPositional() {
+				continue
+			}
+			if (strings.HasPrefix(argument, "--") || o.isSingleDashLongName(argument)) && strings.Contains(argument, "=") {
+				if _, cnt, _ := v.splitEqualChar(argument); cnt > 0 {
+					return 0
+				}
+			}
+			if cnt, _ := v.check(argument); cnt == 0 {
+				continue
+			}
+			if _, ok := v.attachedValue(argument); ok || v.hasConst() {
+				return 0
+			}
+			if v.nargs != nil {
+				return v.countValues(position, args)
+			}
+			return v.size - 1
+		}
+	}
+	return 0
+}
+
+// hideRemainder - finds first positional in arguments and hides it together with all following arguments,
+// so they are not matched as arguments by this Command or its parents. Hidden arguments are restored
+// by restoreRemainder before parsing positionals. Arguments after "--" are hidden too and "--" itself is consumed.
+func (o *Command) hideRemainder(args *[]string) {
+	i := 0
+	for ; i < len(*args); i++ {
+		arg := (*args)[i]
+		if arg == "" {
+			continue
+		}
+		if arg == "--" {
+			(*args)[i] = ""
+			i++
+			break
+		}
+		if !o.isArgument(arg) {
+			break
+		}
+		i += o.skipValues(i, *args)
+	}
+	if i >= len(*args) {
+		return
+	}
+	o.remainder = (*args)[i:]
+	o.remainderValues = make([]string, len(o.remainder))
+	copy(o.remainderValues, o.remainder)
+	for j := range o.remainder {
+		o.remainder[j] = ""
+	}
+}
+
+// restoreRemainder - restores arguments hidden by hideRemainder of Command and its sub-commands
+func (o *Command) restoreRemainder() {
+	if o.remainder != nil {
+		copy(o.remainder, o.remainderValues)
+		o.remainder, o.remainderValues = nil, nil
+	}
+	for _, c := range o.commands {
+		c.restoreRemainder()
+	}
+}
+
+// origin - returns location in response file (`file:line`) of the argument at position in args,
+// or empty string if it was given on command line. Since command names are cut off the beginning of args
+// during parsing, position is counted from the end of the arguments.
+func (o *Command) origin(position int, args []string) string {
+	root := o
+	for root.parent != nil {
+		root = root.parent
+	}
+	i := len(root.origins) - len(args) + position
+	if i < 0 || i >= len(root.origins) {
+		return ""
+	}
+	return root.origins[i]
+}
+
+// originError - prefixes err with location of the argument at position in args, if it came from response file
+func (o *Command) originError(err error, position int, args []string) error {
+	if origin := o.origin(position, args); origin != "" {
+		return fmt.Errorf("%s: %w", origin, err)
+	}
+	return err
+}
+
 //parseArguments - Parses arguments
 func (o *Command) parseArguments(inputArgs *[]string) error {
 	// Iterate over the args
@@ -138,38 +404,81 @@ func (o *Command) parseArguments(inputArgs *[]string) error {
 		}
 		for j := 0; j < len(*inputArgs); j++ {
 			arg := (*inputArgs)[j]
-			if arg == "" {
+			if arg == "" || o.isDurationValue(j, *inputArgs) {
 				continue
 			}
-			if strings.Contains(arg, "=") {
-				splitInd := strings.LastIndex(arg, "=")
-				equalArg := []string{arg[:splitInd], arg[splitInd+1:]}
-				if cnt, err := oarg.check(equalArg[0]); err != nil {
-					return err
+			if (strings.HasPrefix(arg, "--") || o.isSingleDashLongName(arg)) && strings.Contains(arg, "=") {
+				equalArg, cnt, err := oarg.splitEqualChar(arg)
+				if err != nil {
+					return o.originError(err, j, *inputArgs)
 				} else if cnt > 0 { // No args implies we supply default
+					oarg.warnDeprecated(equalArg[0])
 					if equalArg[1] == "" {
-						return fmt.Errorf("not enough arguments for %s", oarg.name())
+						return o.originError(fmt.Errorf("not enough arguments for %s", oarg.name()), j, *inputArgs)
 					}
-					oarg.eqChar = true
-					oarg.size = 1
 					currArg := []string{equalArg[1]}
 					err := oarg.parse(currArg, cnt)
 					if err != nil {
-						return err
+						return o.originError(err, j, *inputArgs)
 					}
-					oarg.reduce(j, inputArgs)
+					// Value is part of the same argument, so nothing else to clear out
+					(*inputArgs)[j] = ""
 					continue
 				}
 			}
 			if cnt, err := oarg.check(arg); err != nil {
-				return err
+				return o.originError(err, j, *inputArgs)
 			} else if cnt > 0 {
+				oarg.warnDeprecated(arg)
+				// Value attached to shorthand argument, e.g. `-ofile` or `-n5`
+				if value, ok := oarg.attachedValue(arg); ok {
+					if value == "" {
+						return o.originError(fmt.Errorf("not enough arguments for %s", oarg.name()), j, *inputArgs)
+					}
+					err := oarg.parse([]string{value}, cnt)
+					if err != nil {
+						return o.originError(err, j, *inputArgs)
+					}
+					oarg.reduce(j, inputArgs)
+					continue
+				}
+				// Argument with optional value does not consume following argument
+				if oarg.hasConst() {
+					err := oarg.parse(nil, cnt)
+					if err != nil {
+						return o.originError(err, j, *inputArgs)
+					}
+					oarg.reduce(j, inputArgs)
+					continue
+				}
+				// Argument with NArgs consumes values up to the next argument
+				if oarg.nargs != nil {
+					count := oarg.countValues(j, *inputArgs)
+					err := oarg.parse((*inputArgs)[j+1:j+1+count], cnt)
+					if err != nil {
+						// Error is caused by values, unless there are none
+						position := j
+						if count > 0 {
+							position = j + 1
+						}
+						return o.originError(err, position, *inputArgs)
+					}
+					oarg.reduce(j, inputArgs)
+					for i := j + 1; i <= j+count; i++ {
+						(*inputArgs)[i] = ""
+					}
+					// "--" only terminates values, so it is consumed too
+					if end := j + 1 + count; end < len(*inputArgs) && (*inputArgs)[end] == "--" {
+						(*inputArgs)[end] = ""
+					}
+					continue
+				}
 				if len(*inputArgs) < j+oarg.size {
-					return fmt.Errorf("not enough arguments for %s", oarg.name())
+					return o.originError(fmt.Errorf("not enough arguments for %s", oarg.name()), j, *inputArgs)
 				}
 				err := oarg.parse((*inputArgs)[j+1:j+oarg.size], cnt)
 				if err != nil {
-					return err
+					return o.originError(err, j+1, *inputArgs)
 				}
 				oarg.reduce(j, inputArgs)
 				continue
@@ -186,6 +495,9 @@ func (o *Command) parseArguments(inputArgs *[]string) error {
 				return err
 			}
 		}
+		if err := oarg.checkValue(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -211,7 +523,7 @@ func (o *Command) parse(args *[]string) error {
 	if o.name == "" {
 		o.name = (*args)[0]
 	} else {
-		if o.name != (*args)[0] && o.parent != nil {
+		if o.normalizeName(o.name) != o.normalizeName((*args)[0]) && o.parent != nil {
 			return nil
 		}
 	}
@@ -227,6 +539,11 @@ func (o *Command) parse(args *[]string) error {
 		return err
 	}
 
+	// Hide everything after the first positional from arguments parsing
+	if o.notInterspersed {
+		o.hideRemainder(args)
+	}
+
 	// Parse arguments if any
 	if err := o.parseArguments(args); err != nil {
 		return err
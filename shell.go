@@ -0,0 +1,160 @@
+package argparse
+
+import "strings"
+
+// shellWord is a single word of command line split by splitShellWords
+type shellWord struct {
+	value string
+	line  int // line where word starts, 1-based
+}
+
+// shellSyntaxError is returned by splitShellWords when input cannot be split into words
+type shellSyntaxError struct {
+	line int
+	msg  string
+}
+
+func (e *shellSyntaxError) Error() string {
+	return e.msg
+}
+
+// splitShellWords - splits input into words the same way POSIX shell does, but without any expansions.
+// Words are separated by unquoted whitespace. Single quotes preserve everything literally,
+// double quotes preserve everything except backslash escapes of `"`, `\`, `$`, "`" and newline.
+// Backslash outside of quotes escapes any character and backslash followed by newline continues the line.
+// Unquoted `#` at the beginning of a word starts a comment, which lasts until the end of the line.
+func splitShellWords(input string) ([]shellWord, error) {
+	words := make([]shellWord, 0)
+	runes := []rune(input)
+	line := 1
+
+	var word strings.Builder
+	inWord := false
+	wordLine := 0
+	startWord := func() {
+		if !inWord {
+			inWord = true
+			wordLine = line
+		}
+	}
+	endWord := func() {
+		if inWord {
+			words = append(words, shellWord{value: word.String(), line: wordLine})
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			endWord()
+			line++
+		case r == ' ' || r == '\t' || r == '\r':
+			endWord()
+		case r == '#' && !inWord:
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case r == '\\':
+			if i+1 >= len(runes) {
+				// Trailing backslash has nothing to escape, so it is kept literally
+				startWord()
+				word.WriteRune(r)
+				break
+			}
+			i++
+			if runes[i] == '\n' {
+				line++
+				break
+			}
+			startWord()
+			word.WriteRune(runes[i])
+		case r == '\'':
+			startWord()
+			start := line
+			for i++; i < len(runes) && runes[i] != '\''; i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, &shellSyntaxError{line: start, msg: "unterminated single quote"}
+			}
+		case r == '"':
+			startWord()
+			start := line
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						line++
+						continue
+					}
+				} else if runes[i] == '\n' {
+					line++
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, &shellSyntaxError{line: start, msg: "unterminated double quote"}
+			}
+		default:
+			startWord()
+			word.WriteRune(r)
+		}
+	}
+	endWord()
+
+	return words, nil
+}
+
+// SplitCommandLine splits command line into arguments the same way POSIX shell does, but without
+// any expansions, so `deploy --env "prod eu" --tag 'a b'` results in
+// `[]string{"deploy", "--env", "prod eu", "--tag", "a b"}`.
+// Single quotes preserve everything literally, double quotes preserve everything except backslash escapes
+// of `"`, `\`, `$`, "`" and newline. Backslash outside of quotes escapes any character.
+// Unquoted `#` at the beginning of an argument starts a comment until the end of the line.
+// Returns an error if a quote is not terminated. JoinCommandLine does the opposite.
+func SplitCommandLine(line string) ([]string, error) {
+	words, err := splitShellWords(line)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, len(words))
+	for i, w := range words {
+		args[i] = w.value
+	}
+	return args, nil
+}
+
+// JoinCommandLine joins arguments into command line, quoting them where necessary, so it can be safely
+// passed to POSIX shell or SplitCommandLine, which returns the same arguments back.
+func JoinCommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteShellWord(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quoteShellWord - quotes word with single quotes, unless it consists of characters that are never special to shell
+func quoteShellWord(word string) string {
+	if word == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range word {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-+=@%:,./", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return word
+	}
+	// Single quote cannot be escaped inside single quotes, so it has to be closed, escaped and reopened
+	return "'" + strings.Replace(word, "'", `'\''`, -1) + "'"
+}
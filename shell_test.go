@@ -0,0 +1,81 @@
+package argparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	type testCase struct {
+		line string
+		args []string
+	}
+	testCases := []testCase{
+		{line: "", args: []string{}},
+		{line: "  deploy\t--env \"prod eu\"   --tag 'a b'  ", args: []string{"deploy", "--env", "prod eu", "--tag", "a b"}},
+		{line: `a\ b c\\d \'e\"`, args: []string{"a b", `c\d`, `'e"`}},
+		{line: `'single $HOME \ "x"' "double \$HOME \\ \"x\" \a"`, args: []string{`single $HOME \ "x"`, `double $HOME \ "x" \a`}},
+		{line: `'' "" x''y --name=" value"`, args: []string{"", "", "xy", "--name= value"}},
+		{line: "one # comment 'x\ntwo#three", args: []string{"one", "two#three"}},
+		{line: "one \\\ntwo \"three\\\nfour\"", args: []string{"one", "two", "threefour"}},
+		{line: `trailing\`, args: []string{`trailing\`}},
+		{line: "привет 'мир'", args: []string{"привет", "мир"}},
+	}
+
+	for _, tc := range testCases {
+		args, err := SplitCommandLine(tc.line)
+		if err != nil {
+			t.Errorf("Test %s failed for %q with error: %s", t.Name(), tc.line, err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(args, tc.args) {
+			t.Errorf("Test %s failed for %q. Want: %q, got: %q", t.Name(), tc.line, tc.args, args)
+		}
+	}
+}
+
+func TestSplitCommandLineFail(t *testing.T) {
+	type testCase struct {
+		line   string
+		errStr string
+	}
+	testCases := []testCase{
+		{line: "deploy --env 'prod", errStr: "unterminated single quote"},
+		{line: `deploy --env "prod \"eu`, errStr: "unterminated double quote"},
+		{line: `deploy --env "prod eu\"`, errStr: "unterminated double quote"},
+	}
+
+	for _, tc := range testCases {
+		_, err := SplitCommandLine(tc.line)
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}
+
+func TestJoinCommandLine(t *testing.T) {
+	type testCase struct {
+		args []string
+		line string
+	}
+	testCases := []testCase{
+		{args: []string{}, line: ""},
+		{args: []string{"deploy", "--env=prod", "-v", "/tmp/a.txt", "user@host:80,81"}, line: "deploy --env=prod -v /tmp/a.txt user@host:80,81"},
+		{args: []string{"prod eu", "", "it's", `$HOME`, "#x", "a\nb", "~"}, line: `'prod eu' '' 'it'\''s' '$HOME' '#x' 'a` + "\n" + `b' '~'`},
+	}
+
+	for _, tc := range testCases {
+		line := JoinCommandLine(tc.args)
+		if line != tc.line {
+			t.Errorf("Test %s failed. Want: %s, got: %s", t.Name(), tc.line, line)
+		}
+		args, err := SplitCommandLine(line)
+		if err != nil {
+			t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			continue
+		}
+		if !reflect.DeepEqual(args, tc.args) {
+			t.Errorf("Test %s failed to split back. Want: %q, got: %q", t.Name(), tc.args, args)
+		}
+	}
+}
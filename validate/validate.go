@@ -0,0 +1,132 @@
+// Package validate provides composable validation functions for argparse Options.Validate.
+//
+// Every constructor returns func([]string) error, which checks each of the values passed to the argument.
+// Returned errors describe the offending value and are prefixed with the argument name by the parser,
+// for example "[--port] value [70000] is out of range [1, 65535]".
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Func is the signature of Options.Validate
+type Func = func(args []string) error
+
+// IntRange checks that every value is an integer in [min, max] range, both ends inclusive.
+// Panics if min is greater than max.
+func IntRange(min, max int) Func {
+	if min > max {
+		panic(fmt.Sprintf("unable to create IntRange validator: min %d is greater than max %d", min, max))
+	}
+	return func(args []string) error {
+		for _, arg := range args {
+			i, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("value [%s] is not an integer", arg)
+			}
+			if i < min || i > max {
+				return fmt.Errorf("value [%s] is out of range [%d, %d]", arg, min, max)
+			}
+		}
+		return nil
+	}
+}
+
+// Regexp checks that every value matches pattern. Pattern is not anchored, so use ^ and $ to match whole value.
+// Panics if pattern cannot be compiled.
+func Regexp(pattern string) Func {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("unable to create Regexp validator: %s", err.Error()))
+	}
+	return func(args []string) error {
+		for _, arg := range args {
+			if !re.MatchString(arg) {
+				return fmt.Errorf("value [%s] does not match [%s]", arg, pattern)
+			}
+		}
+		return nil
+	}
+}
+
+// NonEmpty checks that no value is an empty or whitespace-only string
+func NonEmpty() Func {
+	return func(args []string) error {
+		for _, arg := range args {
+			if strings.TrimSpace(arg) == "" {
+				return errors.New("value must not be empty")
+			}
+		}
+		return nil
+	}
+}
+
+// PathExists checks that every value is a path to an existing file or directory
+func PathExists() Func {
+	return func(args []string) error {
+		for _, arg := range args {
+			if _, err := os.Stat(arg); err != nil {
+				if os.IsNotExist(err) {
+					return fmt.Errorf("path [%s] does not exist", arg)
+				}
+				return fmt.Errorf("path [%s] is not accessible: %w", arg, err)
+			}
+		}
+		return nil
+	}
+}
+
+// OneOf checks that every value is one of allowed values, comparison is case-sensitive
+func OneOf(values ...string) Func {
+	return func(args []string) error {
+		for _, arg := range args {
+			found := false
+			for _, v := range values {
+				if arg == v {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("value [%s] is not one of allowed values %v", arg, values)
+			}
+		}
+		return nil
+	}
+}
+
+// All checks that values pass every one of validators, returns the first error encountered
+func All(validators ...Func) Func {
+	return func(args []string) error {
+		for _, v := range validators {
+			if err := v(args); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Any checks that values pass at least one of validators. If none of them pass,
+// returned error lists errors of all validators joined with " or ".
+func Any(validators ...Func) Func {
+	return func(args []string) error {
+		if len(validators) == 0 {
+			return nil
+		}
+		msgs := make([]string, 0, len(validators))
+		for _, v := range validators {
+			err := v(args)
+			if err == nil {
+				return nil
+			}
+			msgs = append(msgs, err.Error())
+		}
+		return errors.New(strings.Join(msgs, " or "))
+	}
+}
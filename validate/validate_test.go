@@ -0,0 +1,118 @@
+package validate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/akamensky/argparse"
+)
+
+func TestValidators(t *testing.T) {
+	dir, err := ioutil.TempDir("", "argparse-validate")
+	if err != nil {
+		t.Fatalf("Test %s failed with error: %s", t.Name(), err.Error())
+	}
+	defer os.RemoveAll(dir)
+	missing := filepath.Join(dir, "missing")
+
+	type testCase struct {
+		name     string
+		validate Func
+		args     []string
+		errStr   string
+	}
+	testCases := []testCase{
+		{name: "IntRange ok", validate: IntRange(1, 65535), args: []string{"1", "80", "65535"}},
+		{name: "IntRange no values", validate: IntRange(1, 2), args: []string{}},
+		{name: "IntRange not integer", validate: IntRange(1, 65535), args: []string{"80", "http"}, errStr: "value [http] is not an integer"},
+		{name: "IntRange below", validate: IntRange(1, 65535), args: []string{"0"}, errStr: "value [0] is out of range [1, 65535]"},
+		{name: "IntRange above", validate: IntRange(-5, 5), args: []string{"6"}, errStr: "value [6] is out of range [-5, 5]"},
+		{name: "Regexp ok", validate: Regexp(`^[a-z]+$`), args: []string{"abc", "x"}},
+		{name: "Regexp fail", validate: Regexp(`^[a-z]+$`), args: []string{"abc", "A1"}, errStr: "value [A1] does not match [^[a-z]+$]"},
+		{name: "NonEmpty ok", validate: NonEmpty(), args: []string{"a", " b "}},
+		{name: "NonEmpty empty", validate: NonEmpty(), args: []string{"a", ""}, errStr: "value must not be empty"},
+		{name: "NonEmpty blank", validate: NonEmpty(), args: []string{" \t"}, errStr: "value must not be empty"},
+		{name: "PathExists ok", validate: PathExists(), args: []string{dir}},
+		{name: "PathExists missing", validate: PathExists(), args: []string{dir, missing}, errStr: "path [" + missing + "] does not exist"},
+		{name: "OneOf ok", validate: OneOf("json", "yaml"), args: []string{"yaml", "json"}},
+		{name: "OneOf fail", validate: OneOf("json", "yaml"), args: []string{"JSON"}, errStr: "value [JSON] is not one of allowed values [json yaml]"},
+		{name: "All ok", validate: All(NonEmpty(), IntRange(1, 10)), args: []string{"5"}},
+		{name: "All first error", validate: All(NonEmpty(), IntRange(1, 10)), args: []string{""}, errStr: "value must not be empty"},
+		{name: "All second error", validate: All(NonEmpty(), IntRange(1, 10)), args: []string{"11"}, errStr: "value [11] is out of range [1, 10]"},
+		{name: "All empty", validate: All(), args: []string{"x"}},
+		{name: "Any first", validate: Any(IntRange(1, 10), OneOf("auto")), args: []string{"3"}},
+		{name: "Any second", validate: Any(IntRange(1, 10), OneOf("auto")), args: []string{"auto"}},
+		{name: "Any none", validate: Any(IntRange(1, 10), OneOf("auto")), args: []string{"off"}, errStr: "value [off] is not an integer or value [off] is not one of allowed values [auto]"},
+		{name: "Any empty", validate: Any(), args: []string{"x"}},
+	}
+
+	for _, tc := range testCases {
+		err := tc.validate(tc.args)
+		if tc.errStr == "" {
+			if err != nil {
+				t.Errorf("Test %s failed for [%s] with error: %s", t.Name(), tc.name, err.Error())
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s failed for [%s]. Expected error [%s], got [%+v]", t.Name(), tc.name, tc.errStr, err)
+		}
+	}
+}
+
+func TestValidatorsPanic(t *testing.T) {
+	type testCase struct {
+		name           string
+		create         func()
+		failureMessage string
+	}
+	testCases := []testCase{
+		{name: "IntRange", create: func() { IntRange(10, 1) }, failureMessage: "unable to create IntRange validator: min 10 is greater than max 1"},
+		{name: "Regexp", create: func() { Regexp(`[a-`) }, failureMessage: "unable to create Regexp validator: error parsing regexp: missing closing ]: `[a-`"},
+	}
+
+	for _, tc := range testCases {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || r.(string) != tc.failureMessage {
+					t.Errorf("Test %s failed for [%s]. Expected panic [%s], got [%+v]", t.Name(), tc.name, tc.failureMessage, r)
+				}
+			}()
+			tc.create()
+		}()
+	}
+}
+
+func TestValidatorsWithParser(t *testing.T) {
+	type testCase struct {
+		args   []string
+		errStr string
+	}
+	testCases := []testCase{
+		{args: []string{"progname", "--port", "8080", "--format", "json"}},
+		{args: []string{"progname", "--port", "70000"}, errStr: "[-p|--port] value [70000] is out of range [1, 65535]"},
+		{args: []string{"progname", "--format", "xml"}, errStr: "[-f|--format] value [xml] is not one of allowed values [json yaml]"},
+		{args: []string{"progname", "--ports", "80,443"}},
+		{args: []string{"progname", "--ports", "80,0"}, errStr: "[--ports] value [0] is out of range [1, 65535]"},
+	}
+
+	for _, tc := range testCases {
+		p := argparse.NewParser("progname", "description")
+		p.Int("p", "port", &argparse.Options{Validate: IntRange(1, 65535)})
+		p.String("f", "format", &argparse.Options{Validate: All(NonEmpty(), OneOf("json", "yaml"))})
+		p.IntList("", "ports", &argparse.Options{Separator: ",", Validate: IntRange(1, 65535)})
+
+		err := p.Parse(tc.args)
+		if tc.errStr == "" {
+			if err != nil {
+				t.Errorf("Test %s failed with error: %s", t.Name(), err.Error())
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.errStr {
+			t.Errorf("Test %s expected [%s], got [%+v]", t.Name(), tc.errStr, err)
+		}
+	}
+}